@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"rune/pkg/errors"
+	"rune/pkg/rune"
+)
+
+// runFormat runs the fmt command on files and directories of .rn files. It prints the
+// formatted files, lists those that are not formatted with --check, or rewrites them
+// with --write.
+func runFormat(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	flags.Usage = printUsage
+	check := flags.Bool("check", false, "list the files that are not formatted and fail if there are any")
+	write := flags.Bool("write", false, "rewrite the files that are not formatted")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 || (*check && *write) {
+		printUsage()
+	}
+
+	paths, err := findSources(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding files: %v\n", err)
+		return exitCodeError
+	}
+
+	code := exitCodeOk
+
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+			code = exitCodeError
+			continue
+		}
+
+		formatted, err := rune.Format(source)
+		if err != nil {
+			reportFormatError(path, err)
+			code = max(code, exitCodeParseError)
+			continue
+		}
+
+		switch {
+		case *check:
+			if !bytes.Equal(source, formatted) {
+				fmt.Println(path)
+				code = max(code, exitCodeError)
+			}
+		case *write:
+			if bytes.Equal(source, formatted) {
+				continue
+			}
+
+			if err := os.WriteFile(path, formatted, 0o644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing file: %v\n", err)
+				code = exitCodeError
+			}
+		default:
+			os.Stdout.Write(formatted)
+		}
+	}
+
+	return code
+}
+
+// findSources expands the directories among paths to the .rn files they contain.
+func findSources(paths []string) ([]string, error) {
+	var sources []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			sources = append(sources, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !entry.IsDir() && filepath.Ext(path) == runeExtension {
+				sources = append(sources, path)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sources, nil
+}
+
+// reportFormatError prints why a file could not be formatted, syntax errors with the
+// lines they point at.
+func reportFormatError(path string, err error) {
+	compileErr, ok := err.(*errors.CompileError)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", path, err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Cannot format %s:\n", path)
+
+	for _, e := range compileErr.Errors() {
+		printError(os.Stderr, e)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"rune/pkg/errors"
+	"rune/pkg/rune"
+	"slices"
+	"strings"
+)
+
+// runLint runs the lint command on files and directories of .rn files and prints the
+// warnings found. It fails if there are any.
+func runLint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	flags.Usage = printUsage
+	rules := lintFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		printUsage()
+	}
+
+	enabled, err := rules()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitCodeError
+	}
+
+	paths, err := findSources(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding files: %v\n", err)
+		return exitCodeError
+	}
+
+	code := exitCodeOk
+
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+			code = exitCodeError
+			continue
+		}
+
+		warnings, err := rune.Lint(source, enabled)
+		if err != nil {
+			reportLintError(path, err)
+			code = max(code, exitCodeParseError)
+			continue
+		}
+
+		for _, w := range warnings {
+			fmt.Printf("%s:%d: %s [%s]%s\n", path, w.Token.Line, w.Message, w.Rule, errors.Snippet(w.Token))
+			code = max(code, exitCodeError)
+		}
+	}
+
+	return code
+}
+
+// lintFlags defines the flags that pick the rules of the linter on flags. The returned
+// function gives the enabled rules once the flags are parsed: every rule, or those of
+// --enable, without those of --disable.
+func lintFlags(flags *flag.FlagSet) func() ([]string, error) {
+	var enable, disable []string
+	flags.Func("enable", "comma-separated rules to check instead of all of them", allow(&enable))
+	flags.Func("disable", "comma-separated rules not to check", allow(&disable))
+
+	return func() ([]string, error) {
+		for _, rule := range slices.Concat(enable, disable) {
+			if !slices.Contains(rune.LintRules, rule) {
+				return nil, fmt.Errorf("unknown rule '%s', the rules are %s", rule, strings.Join(rune.LintRules, ", "))
+			}
+		}
+
+		enabled := rune.LintRules
+		if len(enable) > 0 {
+			enabled = enable
+		}
+
+		return slices.DeleteFunc(slices.Clone(enabled), func(rule string) bool {
+			return slices.Contains(disable, rule)
+		}), nil
+	}
+}
+
+// reportLintError prints why a file could not be linted.
+func reportLintError(path string, err error) {
+	compileErr, ok := err.(*errors.CompileError)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error linting %s: %v\n", path, err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Cannot lint %s:\n", path)
+
+	for _, e := range compileErr.Errors() {
+		printError(os.Stderr, e)
+	}
+}
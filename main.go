@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"rune/pkg/errors"
+	"rune/pkg/lsp"
 	"rune/pkg/rune"
+	"rune/pkg/sandbox"
 	"strings"
 )
 
@@ -31,12 +37,31 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "Copyright: Alexander Satretdinov (c), 2025\n")
 	fmt.Fprintf(os.Stderr, "Based on the Lox programming language and Robert Nystrom's book.\n")
 	fmt.Fprintf(os.Stderr, "A simple interpreter for processing and evaluating scripts.\n\n")
-	fmt.Fprintf(os.Stderr, "Usage: rune <command> <filename>\n")
+	fmt.Fprintf(os.Stderr, "Usage: rune <command> [options] <filename>\n")
+	fmt.Fprintf(os.Stderr, "       rune test [options] [file|dir|filter]\n")
+	fmt.Fprintf(os.Stderr, "       rune fmt [--check|--write] <file|dir>...\n")
+	fmt.Fprintf(os.Stderr, "       rune lint [--enable=rule,...] [--disable=rule,...] <file|dir>...\n")
+	fmt.Fprintf(os.Stderr, "       rune repl\n")
+	fmt.Fprintf(os.Stderr, "       rune lsp\n")
 	fmt.Fprintf(os.Stderr, "Commands:\n")
 	fmt.Fprintf(os.Stderr, "  tokenize  - Tokenizes the input file\n")
 	fmt.Fprintf(os.Stderr, "  evaluate  - Evaluates a single expression from the input file\n")
 	fmt.Fprintf(os.Stderr, "  run       - Runs the program from the input file\n")
+	fmt.Fprintf(os.Stderr, "  test      - Runs the .rn tests in test/ and their test blocks, --format=text|json|junit, --blocks only checks the blocks\n")
+	fmt.Fprintf(os.Stderr, "  fmt       - Prints the files formatted, --check lists those that are not and --write rewrites them\n")
+	fmt.Fprintf(os.Stderr, "  lint      - Prints the warnings of the linter for the files, rules: %s\n", strings.Join(rune.LintRules, ", "))
+	fmt.Fprintf(os.Stderr, "  lsp       - Starts a language server speaking the Language Server Protocol over stdio\n")
+	fmt.Fprintf(os.Stderr, "  repl      - Starts an interactive session, the default without arguments\n")
 	fmt.Fprintf(os.Stderr, "  version   - Prints the version of the interpreter\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	fmt.Fprintf(os.Stderr, "  --engine=tree|vm - Runs the program by walking the syntax tree (default) or as bytecode\n")
+	fmt.Fprintf(os.Stderr, "  --timeout=5s     - Stops the program after the given time\n")
+	fmt.Fprintf(os.Stderr, "  --max-steps=N    - Stops the program after N loop iterations and calls\n")
+	fmt.Fprintf(os.Stderr, "  --max-alloc=N    - Stops the program after allocating about N bytes\n")
+	fmt.Fprintf(os.Stderr, "  --allow-net=host - Lets the program fetch from the given hosts, * for any\n")
+	fmt.Fprintf(os.Stderr, "  --allow-read=dir - Lets the program read files in the given directories, * for any\n")
+	fmt.Fprintf(os.Stderr, "  --allow-env=name - Lets the program read the given environment variables, * for any\n")
+	fmt.Fprintf(os.Stderr, "  --allow-all      - Grants every permission\n")
 	os.Exit(1)
 }
 
@@ -79,43 +104,78 @@ func evaluate(fileContents []byte) int {
 	return exitCodeOk
 }
 
-func run(fileContents []byte) int {
-	tokens, errors := rune.Scan(fileContents)
-
-	if len(errors) > 0 {
-		for _, err := range errors {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-		}
+// printError writes an error with the source line it points at underlined.
+func printError(w io.Writer, err error) {
+	fmt.Fprintln(w, errors.Annotate(err))
+}
 
-		return exitCodeParseError
-	}
+// run runs a program and returns the exit code of the run command. Errors are printed
+// to opts.Stderr.
+func run(fileName string, fileContents []byte, opts rune.Options) int {
+	opts.Path = fileName
 
-	stmts, err := rune.ParseStmts(tokens)
+	runtime, err := rune.NewRuntime(opts)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
-		return exitCodeParseError
+		fmt.Fprintf(opts.Stderr, "Error: %v\n", err)
+		return exitCodeError
 	}
 
-	interpreter := rune.NewInterpreter()
-	resolver := rune.NewResolver(interpreter)
+	return exitCode(runtime.Run(context.Background(), fileContents))
+}
 
-	if err := resolver.ResolveStmts(stmts); err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+// exitCode returns the exit code of a run that ended with err.
+func exitCode(err error) int {
+	switch err.(type) {
+	case nil:
+		return exitCodeOk
+	case *errors.CompileError:
 		return exitCodeParseError
+	default:
+		return exitCodeEvalError
 	}
+}
 
-	if err := interpreter.EvaluateStmts(stmts); err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
-		return exitCodeEvalError
+// runFlags defines the flags that configure a run on flags. The returned function
+// builds the options once the flags are parsed.
+func runFlags(flags *flag.FlagSet) func() rune.Options {
+	engineName := flags.String("engine", "tree", "engine that runs the program: tree or vm")
+
+	var opts rune.Options
+	flags.DurationVar(&opts.Limits.Timeout, "timeout", 0, "stop the program after this long, e.g. 5s")
+	flags.IntVar(&opts.Limits.MaxSteps, "max-steps", 0, "stop the program after this many loop iterations and calls")
+	flags.IntVar(&opts.Limits.MaxAllocation, "max-alloc", 0, "stop the program after allocating about this many bytes")
+	flags.Func("allow-net", "comma-separated hosts the program can fetch from", allow(&opts.Permissions.Net))
+	flags.Func("allow-read", "comma-separated directories the program can read files in", allow(&opts.Permissions.Read))
+	flags.Func("allow-env", "comma-separated environment variables the program can read", allow(&opts.Permissions.Env))
+	allowAll := flags.Bool("allow-all", false, "grant every permission")
+
+	return func() rune.Options {
+		opts.Engine = rune.Engine(*engineName)
+		if *allowAll {
+			opts.Permissions = sandbox.AllowAll()
+		}
+
+		return opts
 	}
+}
 
-	return exitCodeOk
+// allow returns a flag that adds comma-separated values to a list, such as the
+// permissions granted or the rules of the linter. It can be repeated.
+func allow(list *[]string) func(string) error {
+	return func(value string) error {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*list = append(*list, item)
+			}
+		}
+
+		return nil
+	}
 }
 
 func main() {
 	if len(os.Args) < 2 {
-		printUsage()
-		return
+		os.Exit(startRepl())
 	}
 
 	command := os.Args[1]
@@ -124,12 +184,39 @@ func main() {
 		return
 	}
 
-	if len(os.Args) < 3 {
+	if command == "repl" {
+		os.Exit(startRepl())
+	}
+
+	if command == "test" {
+		os.Exit(runTests(os.Args[2:]))
+	}
+
+	if command == "fmt" {
+		os.Exit(runFormat(os.Args[2:]))
+	}
+
+	if command == "lint" {
+		os.Exit(runLint(os.Args[2:]))
+	}
+
+	if command == "lsp" {
+		os.Exit(lsp.NewServer(version).Serve(os.Stdin, os.Stdout))
+	}
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.Usage = printUsage
+	options := runFlags(flags)
+	flags.Parse(os.Args[2:])
+	opts := options()
+	opts.Stderr = os.Stderr
+
+	if flags.NArg() < 1 {
 		printUsage()
 		return
 	}
 
-	fileName := os.Args[2]
+	fileName := flags.Arg(0)
 
 	if !strings.HasSuffix(fileName, runeExtension) {
 		fmt.Fprintf(os.Stderr, "Error: Only .rn files are supported. Provided file: %s\n", fileName)
@@ -148,7 +235,7 @@ func main() {
 	case "evaluate":
 		os.Exit(evaluate(fileContents))
 	case "run":
-		os.Exit(run(fileContents))
+		os.Exit(run(fileName, fileContents, opts))
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		printUsage()
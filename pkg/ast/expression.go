@@ -15,6 +15,10 @@ type ExprVisitor interface {
 	VisitIndexExpr(indexExpr *IndexExpr) (any, error)
 	VisitSetIndexExpr(setIndexExpr *SetIndexExpr) (any, error)
 	VisitObjectExpr(objectExpr *ObjectExpr) (any, error)
+	VisitThisExpr(thisExpr *ThisExpr) (any, error)
+	VisitGetExpr(getExpr *GetExpr) (any, error)
+	VisitSetExpr(setExpr *SetExpr) (any, error)
+	VisitFunctionExpr(functionExpr *FunctionExpr) (any, error)
 }
 
 type Expr interface {
@@ -60,10 +64,13 @@ func NewUnaryExpr(right Expr, operator Token) Expr {
 type LiteralExpr struct {
 	TokenType TokenType
 	Value     any
+	// Token is the literal as written, it has an empty lexeme for literals the parser
+	// makes up, such as the condition of a for loop without one.
+	Token Token
 }
 
-func NewLiteralExpr(tokenType TokenType, value any) Expr {
-	return &LiteralExpr{TokenType: tokenType, Value: value}
+func NewLiteralExpr(tokenType TokenType, value any, token Token) Expr {
+	return &LiteralExpr{TokenType: tokenType, Value: value, Token: token}
 }
 
 type VarExpr struct {
@@ -93,22 +100,27 @@ func NewGroupingExpr(expr Expr) Expr {
 }
 
 type CallExpr struct {
+	// Token is the closing parenthesis and Open the opening one.
 	Token  Token
+	Open   Token
 	Callee Expr
 	Args   []Expr
 }
 
-func NewCallExpr(token Token, callee Expr, args []Expr) Expr {
-	return &CallExpr{Token: token, Callee: callee, Args: args}
+func NewCallExpr(token Token, open Token, callee Expr, args []Expr) Expr {
+	return &CallExpr{Token: token, Open: open, Callee: callee, Args: args}
 }
 
 type ArrayExpr struct {
 	TokenType TokenType
-	Items     []Expr
+	// Token is the opening bracket and End the closing one.
+	Token Token
+	Items []Expr
+	End   Token
 }
 
-func NewArrayExpr(tokenType TokenType, items []Expr) Expr {
-	return &ArrayExpr{TokenType: tokenType, Items: items}
+func NewArrayExpr(tokenType TokenType, token Token, items []Expr, end Token) Expr {
+	return &ArrayExpr{TokenType: tokenType, Token: token, Items: items, End: end}
 }
 
 type IndexExpr struct {
@@ -134,11 +146,57 @@ func NewSetIndexExpr(token Token, array Expr, index Expr, value Expr) Expr {
 
 type ObjectExpr struct {
 	TokenType TokenType
-	Pairs     map[string]Expr
+	// Token is the opening brace and End the closing one.
+	Token Token
+	// Pairs are in the order they are written, a key written twice is kept twice.
+	Pairs []ObjectPair
+	End   Token
+}
+
+// ObjectPair is a key of an object literal and the expression of its value.
+type ObjectPair struct {
+	Key   Token
+	Value Expr
+}
+
+func NewObjectExpr(tokenType TokenType, token Token, pairs []ObjectPair, end Token) Expr {
+	return &ObjectExpr{TokenType: tokenType, Token: token, Pairs: pairs, End: end}
+}
+
+type GetExpr struct {
+	Object Expr
+	Name   Token
+}
+
+func NewGetExpr(object Expr, name Token) Expr {
+	return &GetExpr{Object: object, Name: name}
+}
+
+type SetExpr struct {
+	Object Expr
+	Name   Token
+	Value  Expr
+}
+
+func NewSetExpr(object Expr, name Token, value Expr) Expr {
+	return &SetExpr{Object: object, Name: name, Value: value}
+}
+
+// FunctionExpr is an anonymous function, its declaration has a name token with an empty lexeme.
+type FunctionExpr struct {
+	Declaration *FunctionStmt
+}
+
+func NewFunctionExpr(name Token, open Token, parameters []Token, close Token, body []Stmt, end Token) Expr {
+	return &FunctionExpr{Declaration: &FunctionStmt{Name: name, Open: open, Parameters: parameters, Close: close, Body: body, End: end}}
+}
+
+type ThisExpr struct {
+	Keyword Token
 }
 
-func NewObjectExpr(tokenType TokenType, pairs map[string]Expr) Expr {
-	return &ObjectExpr{TokenType: tokenType, Pairs: pairs}
+func NewThisExpr(keyword Token) Expr {
+	return &ThisExpr{Keyword: keyword}
 }
 
 func (n *ObjectExpr) Accept(v ExprVisitor) (any, error) {
@@ -214,3 +272,27 @@ func (n *CallExpr) Accept(v ExprVisitor) (any, error) {
 func (n *SetIndexExpr) Accept(v ExprVisitor) (any, error) {
 	return v.VisitSetIndexExpr(n)
 }
+
+func (n *ThisExpr) Accept(v ExprVisitor) (any, error) {
+	return v.VisitThisExpr(n)
+}
+
+func (n *GetExpr) Accept(v ExprVisitor) (any, error) {
+	return v.VisitGetExpr(n)
+}
+
+func (n *GetExpr) String() string {
+	return fmt.Sprintf("(get %v %s)", n.Object, n.Name.Lexeme)
+}
+
+func (n *SetExpr) Accept(v ExprVisitor) (any, error) {
+	return v.VisitSetExpr(n)
+}
+
+func (n *SetExpr) String() string {
+	return fmt.Sprintf("(set %v %s %v)", n.Object, n.Name.Lexeme, n.Value)
+}
+
+func (n *FunctionExpr) Accept(v ExprVisitor) (any, error) {
+	return v.VisitFunctionExpr(n)
+}
@@ -0,0 +1,887 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+const indentation = "    "
+
+// Format renders a program in the canonical style of "rune fmt": four spaces of
+// indentation, single spaces around binary operators, one statement per line, at most
+// one blank line where the source had any, and a trailing comma after every item of an
+// array or object literal written over several lines.
+//
+// The comments are the trivia the scanner kept. A comment is written before the code it
+// preceded, or at the end of the line of the code it followed, so formatting a formatted
+// program changes nothing.
+func Format(stmts []Stmt, comments []Comment) string {
+	f := &formatter{comments: comments, lineStart: true}
+
+	f.stmts(stmts)
+
+	for len(f.comments) > 0 {
+		f.comment()
+	}
+
+	f.line()
+
+	return f.sb.String()
+}
+
+type formatter struct {
+	sb     strings.Builder
+	indent int
+	// comments are those not written yet.
+	comments []Comment
+	// lineStart is true until something is written on the current line, and commented
+	// is true once the line ends with a comment so that no code can follow on it.
+	lineStart bool
+	commented bool
+	// lastLine is the source line of the last statement or comment written in the
+	// current block, 0 when there is none yet.
+	lastLine int
+}
+
+func (f *formatter) write(s string) {
+	if f.commented {
+		f.newline()
+	}
+
+	if f.lineStart {
+		f.sb.WriteString(strings.Repeat(indentation, f.indent))
+		f.lineStart = false
+	}
+
+	f.sb.WriteString(s)
+}
+
+func (f *formatter) newline() {
+	f.sb.WriteByte('\n')
+	f.lineStart = true
+	f.commented = false
+}
+
+// line starts a new line unless nothing was written on the current one.
+func (f *formatter) line() {
+	if !f.lineStart {
+		f.newline()
+	}
+}
+
+// begin starts the line of a statement, member or comment found at a position of the
+// source. A blank line is kept before it if the source has one, unless it comes first
+// in its block.
+func (f *formatter) begin(pos Position) {
+	f.line()
+
+	if pos.Source == nil {
+		return
+	}
+
+	if f.lastLine > 0 && pos.Line > f.lastLine && blankLineBefore(pos) && f.sb.Len() > 0 {
+		f.newline()
+	}
+
+	f.lastLine = pos.Line
+}
+
+// blankLineBefore reports whether the line above the one of a position is blank.
+func blankLineBefore(pos Position) bool {
+	text := pos.Source.Text
+
+	start := strings.LastIndexByte(text[:pos.Offset], '\n')
+	if start <= 0 {
+		return false
+	}
+
+	previous := strings.LastIndexByte(text[:start], '\n') + 1
+
+	return strings.TrimSpace(text[previous:start]) == ""
+}
+
+// flush writes the comments found before a position of the source.
+func (f *formatter) flush(pos Position) {
+	if pos.Source == nil {
+		return
+	}
+
+	for len(f.comments) > 0 && f.comments[0].Offset < pos.Offset {
+		f.comment()
+	}
+}
+
+// comment writes the next comment, at the end of the current line if it followed code
+// and the line has no comment yet.
+func (f *formatter) comment() {
+	c := f.comments[0]
+	f.comments = f.comments[1:]
+
+	text := strings.TrimRight(c.Text, " \t")
+
+	if c.Trailing && !f.lineStart && !f.commented {
+		f.sb.WriteString(" " + text)
+	} else {
+		f.begin(c.Position)
+		f.write(text)
+	}
+
+	f.commented = true
+	f.lastLine = c.Line
+}
+
+// hasComments reports whether comments not written yet lie between two tokens.
+func (f *formatter) hasComments(from Token, to Token) bool {
+	for _, c := range f.comments {
+		if c.Offset > from.Offset && c.Offset < to.Offset {
+			return true
+		}
+	}
+
+	return false
+}
+
+// between reports whether comments not written yet lie between two tokens outside of
+// the items in them, which start and end at the given positions.
+func (f *formatter) between(open Token, close Token, starts []Position, ends []Position) bool {
+	if open.Source == nil || close.Source == nil {
+		return false
+	}
+
+	for _, c := range f.comments {
+		if c.Offset <= open.Offset || c.Offset >= close.Offset {
+			continue
+		}
+
+		inside := false
+		for i := range starts {
+			if c.Offset > starts[i].Offset && c.Offset < ends[i].Offset {
+				inside = true
+				break
+			}
+		}
+
+		if !inside {
+			return true
+		}
+	}
+
+	return false
+}
+
+// multiline reports whether a bracketed construct spans several lines of the source or
+// encloses comments, it is then written with one item per line.
+func (f *formatter) multiline(open Token, close Token) bool {
+	if open.Source == nil || close.Source == nil {
+		return false
+	}
+
+	return close.Line > open.Line || f.hasComments(open, close)
+}
+
+func (f *formatter) stmts(stmts []Stmt) {
+	for _, stmt := range stmts {
+		start := StmtStart(stmt)
+
+		f.flush(start)
+		f.begin(start)
+		f.stmt(stmt)
+	}
+}
+
+func (f *formatter) stmt(stmt Stmt) {
+	// The formatter never fails, the error is part of the visitor signature only.
+	_ = stmt.Accept(f)
+}
+
+func (f *formatter) expr(expr Expr) {
+	_, _ = expr.Accept(f)
+}
+
+// block writes statements in braces, end is the closing brace.
+func (f *formatter) block(stmts []Stmt, end Token) {
+	f.write("{")
+	f.indent++
+
+	f.lastLine = 0
+	length := f.sb.Len()
+
+	f.stmts(stmts)
+	f.flush(end.Position)
+
+	f.indent--
+
+	if f.sb.Len() > length {
+		f.line()
+	}
+
+	f.write("}")
+}
+
+// body writes the body of a control flow statement on the line of its header.
+func (f *formatter) body(stmt Stmt) {
+	f.write(" ")
+
+	if block, ok := stmt.(*BlockStmt); ok && block.End.Lexeme != "" {
+		f.block(block.Stmts, block.End)
+		return
+	}
+
+	f.stmt(stmt)
+}
+
+// clause writes the keyword of an else, catch or finally clause, after the comments
+// found before it. It stays on the line of a closing brace unless a comment ends it.
+func (f *formatter) clause(keyword string, token Token, afterBrace bool) {
+	f.flush(token.Position)
+
+	if afterBrace && !f.commented {
+		f.write(" " + keyword)
+		return
+	}
+
+	f.line()
+	f.write(keyword)
+}
+
+func (f *formatter) function(fn *FunctionStmt) {
+	f.write(fn.Name.Lexeme)
+	f.parameters(fn)
+	f.write(" ")
+	f.block(fn.Body, fn.End)
+}
+
+// parameters writes the parameter list of a function, one parameter per line if comments
+// lie between them.
+func (f *formatter) parameters(fn *FunctionStmt) {
+	open, close := fn.Open, fn.Close
+
+	starts := make([]Position, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		starts[i] = param.Position
+	}
+
+	f.list(open, close, starts, f.between(open, close, nil, nil), false, func(i int) {
+		f.write(fn.Parameters[i].Lexeme)
+	})
+}
+
+// items writes the items of a literal between its brackets, one per line if the literal
+// is multiline.
+func (f *formatter) items(open Token, close Token, starts []Position, item func(i int)) {
+	f.list(open, close, starts, f.multiline(open, close), true, item)
+}
+
+// list writes items separated by commas between brackets. A multiline list has one item
+// per line, each followed by a comma unless it is the last and trailingComma is false.
+func (f *formatter) list(open Token, close Token, starts []Position, multiline bool, trailingComma bool, item func(i int)) {
+	if !multiline {
+		f.write(open.Lexeme)
+
+		for i := range starts {
+			if i > 0 {
+				f.write(", ")
+			}
+
+			item(i)
+		}
+
+		f.write(close.Lexeme)
+
+		return
+	}
+
+	f.write(open.Lexeme)
+	f.indent++
+	f.lastLine = 0
+
+	for i, start := range starts {
+		f.flush(start)
+		f.begin(start)
+		item(i)
+
+		if trailingComma || i < len(starts)-1 {
+			f.write(",")
+		}
+	}
+
+	f.flush(close.Position)
+	f.indent--
+	f.line()
+	f.write(close.Lexeme)
+}
+
+// loop writes a while loop, or the for loop it was desugared from with its initializer.
+func (f *formatter) loop(stmt *WhileStmt, initializer Stmt) {
+	if stmt.Label.Lexeme != "" {
+		f.write(stmt.Label.Lexeme + ": ")
+	}
+
+	if stmt.Keyword.Lexeme != "for" {
+		f.write("while (")
+		f.expr(stmt.Condition)
+		f.write(")")
+		f.body(stmt.Body)
+
+		return
+	}
+
+	f.write("for (")
+
+	if initializer != nil {
+		f.stmt(initializer)
+	} else {
+		f.write(";")
+	}
+
+	// A for loop without a condition gets one the parser made up.
+	if literal, ok := stmt.Condition.(*LiteralExpr); !ok || literal.Token.Lexeme != "" {
+		f.write(" ")
+		f.expr(stmt.Condition)
+	}
+
+	f.write(";")
+
+	if stmt.Increment != nil {
+		f.write(" ")
+		f.expr(stmt.Increment)
+	}
+
+	f.write(")")
+	f.body(stmt.Body)
+}
+
+func (f *formatter) VisitPrintStmt(stmt *PrintStmt) error {
+	// print(a) is as common as print a, the parentheses stay next to the keyword.
+	if _, ok := stmt.Expr.(*GroupingExpr); ok {
+		f.write("print")
+	} else {
+		f.write("print ")
+	}
+
+	f.expr(stmt.Expr)
+	f.write(";")
+
+	return nil
+}
+
+func (f *formatter) VisitExprStmt(stmt *ExprStmt) error {
+	f.expr(stmt.Expr)
+	f.write(";")
+
+	return nil
+}
+
+func (f *formatter) VisitVarStmt(stmt *VarStmt) error {
+	f.write("var " + stmt.Name.Lexeme)
+
+	if stmt.Initializer != nil {
+		f.write(" = ")
+		f.expr(stmt.Initializer)
+	}
+
+	f.write(";")
+
+	return nil
+}
+
+func (f *formatter) VisitBlockStmt(stmt *BlockStmt) error {
+	// The block wrapped around a for loop with an initializer has no braces.
+	if stmt.Token.Lexeme == "" && len(stmt.Stmts) == 2 {
+		if loop, ok := stmt.Stmts[1].(*WhileStmt); ok {
+			f.loop(loop, stmt.Stmts[0])
+			return nil
+		}
+	}
+
+	f.block(stmt.Stmts, stmt.End)
+
+	return nil
+}
+
+func (f *formatter) VisitIfStmt(stmt *IfStmt) error {
+	f.write("if (")
+	f.expr(stmt.Condition)
+	f.write(")")
+	f.body(stmt.Then)
+
+	if stmt.El == nil {
+		return nil
+	}
+
+	block, ok := stmt.Then.(*BlockStmt)
+	f.clause("else", stmt.ElseKeyword, ok && block.End.Lexeme != "")
+
+	if elseIf, ok := stmt.El.(*IfStmt); ok {
+		f.write(" ")
+		f.stmt(elseIf)
+	} else {
+		f.body(stmt.El)
+	}
+
+	return nil
+}
+
+func (f *formatter) VisitWhileStmt(stmt *WhileStmt) error {
+	f.loop(stmt, nil)
+
+	return nil
+}
+
+func (f *formatter) VisitFunctionStmt(stmt *FunctionStmt) error {
+	f.write("fun ")
+	f.function(stmt)
+
+	return nil
+}
+
+func (f *formatter) VisitReturnStmt(stmt *ReturnStmt) error {
+	f.write("return")
+
+	if stmt.Value != nil {
+		f.write(" ")
+		f.expr(stmt.Value)
+	}
+
+	f.write(";")
+
+	return nil
+}
+
+func (f *formatter) VisitClassStmt(stmt *ClassStmt) error {
+	f.write("class " + stmt.Name.Lexeme + " {")
+	f.indent++
+
+	f.lastLine = 0
+	length := f.sb.Len()
+
+	for _, method := range stmt.Methods {
+		f.flush(method.Name.Position)
+		f.begin(method.Name.Position)
+		f.function(method)
+	}
+
+	f.flush(stmt.End.Position)
+	f.indent--
+
+	if f.sb.Len() > length {
+		f.line()
+	}
+
+	f.write("}")
+
+	return nil
+}
+
+func (f *formatter) VisitBreakStmt(stmt *BreakStmt) error {
+	f.jump("break", stmt.Label)
+
+	return nil
+}
+
+func (f *formatter) VisitContinueStmt(stmt *ContinueStmt) error {
+	f.jump("continue", stmt.Label)
+
+	return nil
+}
+
+func (f *formatter) jump(keyword string, label Token) {
+	if label.Lexeme != "" {
+		keyword += " " + label.Lexeme
+	}
+
+	f.write(keyword + ";")
+}
+
+func (f *formatter) VisitTryStmt(stmt *TryStmt) error {
+	f.write("try ")
+	f.block(stmt.Body, stmt.BodyEnd)
+
+	if stmt.CatchBody != nil {
+		f.clause("catch", stmt.CatchKeyword, true)
+		f.write(" ")
+
+		if stmt.CatchName.Lexeme != "" {
+			f.write("(" + stmt.CatchName.Lexeme + ") ")
+		}
+
+		f.block(stmt.CatchBody, stmt.CatchEnd)
+	}
+
+	if stmt.FinallyBody != nil {
+		f.clause("finally", stmt.FinallyKeyword, true)
+		f.write(" ")
+		f.block(stmt.FinallyBody, stmt.FinallyEnd)
+	}
+
+	return nil
+}
+
+func (f *formatter) VisitThrowStmt(stmt *ThrowStmt) error {
+	f.write("throw ")
+	f.expr(stmt.Value)
+	f.write(";")
+
+	return nil
+}
+
+func (f *formatter) VisitImportStmt(stmt *ImportStmt) error {
+	f.write("import ")
+
+	if len(stmt.Names) > 0 {
+		names := make([]string, len(stmt.Names))
+		for i, name := range stmt.Names {
+			names[i] = name.Lexeme
+		}
+
+		f.write("{ " + strings.Join(names, ", ") + " } from ")
+	}
+
+	f.write(stmt.Path.Lexeme)
+
+	if stmt.Alias.Lexeme != "" {
+		f.write(" as " + stmt.Alias.Lexeme)
+	}
+
+	f.write(";")
+
+	return nil
+}
+
+func (f *formatter) VisitExportStmt(stmt *ExportStmt) error {
+	f.write("export ")
+	f.stmt(stmt.Declaration)
+
+	return nil
+}
+
+func (f *formatter) VisitTestStmt(stmt *TestStmt) error {
+	f.write("test " + stmt.Name.Lexeme + " ")
+	f.block(stmt.Function.Body, stmt.Function.End)
+
+	return nil
+}
+
+func (f *formatter) VisitBinaryExpr(expr *BinaryExpr) (any, error) {
+	f.expr(expr.Left)
+	f.write(" " + expr.Operator.Lexeme + " ")
+	f.expr(expr.Right)
+
+	return nil, nil
+}
+
+func (f *formatter) VisitLiteralExpr(expr *LiteralExpr) (any, error) {
+	switch {
+	case expr.Token.Lexeme != "":
+		f.write(expr.Token.Lexeme)
+	case expr.Value == nil:
+		f.write("nil")
+	default:
+		f.write(fmt.Sprint(expr.Value))
+	}
+
+	return nil, nil
+}
+
+func (f *formatter) VisitGroupingExpr(expr *GroupingExpr) (any, error) {
+	f.write("(")
+	f.expr(expr.Expr)
+	f.write(")")
+
+	return nil, nil
+}
+
+func (f *formatter) VisitUnaryExpr(expr *UnaryExpr) (any, error) {
+	f.write(expr.Operator.Lexeme)
+	f.expr(expr.Right)
+
+	return nil, nil
+}
+
+func (f *formatter) VisitVarExpr(expr *VarExpr) (any, error) {
+	f.write(expr.Name.Lexeme)
+
+	return nil, nil
+}
+
+func (f *formatter) VisitAssignExpr(expr *AssignExpr) (any, error) {
+	f.write(expr.Name.Lexeme + " = ")
+	f.expr(expr.Value)
+
+	return nil, nil
+}
+
+func (f *formatter) VisitLogicalExpr(expr *LogicalExpr) (any, error) {
+	f.expr(expr.Left)
+	f.write(" " + expr.Op.Lexeme + " ")
+	f.expr(expr.Right)
+
+	return nil, nil
+}
+
+func (f *formatter) VisitCallExpr(expr *CallExpr) (any, error) {
+	f.expr(expr.Callee)
+
+	open, close := expr.Open, expr.Token
+
+	starts := make([]Position, len(expr.Args))
+	ends := make([]Position, len(expr.Args))
+	for i, arg := range expr.Args {
+		starts[i], ends[i] = ExprStart(arg), exprEnd(arg)
+	}
+
+	// Only comments between the arguments spread them over several lines, not those
+	// in the body of a function passed as one.
+	f.list(open, close, starts, f.between(open, close, starts, ends), false, func(i int) {
+		f.expr(expr.Args[i])
+	})
+
+	return nil, nil
+}
+
+func (f *formatter) VisitArrayExpr(expr *ArrayExpr) (any, error) {
+	starts := make([]Position, len(expr.Items))
+	for i, item := range expr.Items {
+		starts[i] = ExprStart(item)
+	}
+
+	f.items(expr.Token, expr.End, starts, func(i int) {
+		f.expr(expr.Items[i])
+	})
+
+	return nil, nil
+}
+
+func (f *formatter) VisitIndexExpr(expr *IndexExpr) (any, error) {
+	f.expr(expr.Array)
+	f.write("[")
+	f.expr(expr.Index)
+	f.write("]")
+
+	return nil, nil
+}
+
+func (f *formatter) VisitSetIndexExpr(expr *SetIndexExpr) (any, error) {
+	f.expr(expr.Array)
+	f.write("[")
+	f.expr(expr.Index)
+	f.write("] = ")
+	f.expr(expr.Value)
+
+	return nil, nil
+}
+
+func (f *formatter) VisitObjectExpr(expr *ObjectExpr) (any, error) {
+	starts := make([]Position, len(expr.Pairs))
+	for i, pair := range expr.Pairs {
+		starts[i] = pair.Key.Position
+	}
+
+	open, close := expr.Token, expr.End
+
+	// Pairs get a space inside the braces when they fit on one line: { a: 1 }.
+	if len(expr.Pairs) > 0 && !f.multiline(open, close) {
+		open.Lexeme, close.Lexeme = "{ ", " }"
+	}
+
+	f.items(open, close, starts, func(i int) {
+		f.write(expr.Pairs[i].Key.Lexeme + ": ")
+		f.expr(expr.Pairs[i].Value)
+	})
+
+	return nil, nil
+}
+
+func (f *formatter) VisitThisExpr(_ *ThisExpr) (any, error) {
+	f.write("this")
+
+	return nil, nil
+}
+
+func (f *formatter) VisitGetExpr(expr *GetExpr) (any, error) {
+	f.expr(expr.Object)
+	f.write("." + expr.Name.Lexeme)
+
+	return nil, nil
+}
+
+func (f *formatter) VisitSetExpr(expr *SetExpr) (any, error) {
+	f.expr(expr.Object)
+	f.write("." + expr.Name.Lexeme + " = ")
+	f.expr(expr.Value)
+
+	return nil, nil
+}
+
+func (f *formatter) VisitFunctionExpr(expr *FunctionExpr) (any, error) {
+	fn := expr.Declaration
+
+	if fn.Name.TokenType != ARROW {
+		f.write("fun ")
+		f.parameters(fn)
+		f.write(" ")
+		f.block(fn.Body, fn.End)
+
+		return nil, nil
+	}
+
+	f.parameters(fn)
+	f.write(" => ")
+
+	// An arrow function returning an expression has no braces.
+	if fn.End.Lexeme == "" && len(fn.Body) == 1 {
+		if ret, ok := fn.Body[0].(*ReturnStmt); ok && ret.Value != nil {
+			f.expr(ret.Value)
+			return nil, nil
+		}
+	}
+
+	f.block(fn.Body, fn.End)
+
+	return nil, nil
+}
+
+// StmtStart returns the position of the first token of a statement in the source.
+func StmtStart(stmt Stmt) Position {
+	switch stmt := stmt.(type) {
+	case *VarStmt:
+		return stmt.Keyword.Position
+	case *PrintStmt:
+		return stmt.Keyword.Position
+	case *ExprStmt:
+		return ExprStart(stmt.Expr)
+	case *BlockStmt:
+		if stmt.Token.Lexeme == "" && len(stmt.Stmts) > 0 {
+			return StmtStart(stmt.Stmts[len(stmt.Stmts)-1])
+		}
+
+		return stmt.Token.Position
+	case *IfStmt:
+		return stmt.Keyword.Position
+	case *WhileStmt:
+		if stmt.Label.Lexeme != "" {
+			return stmt.Label.Position
+		}
+
+		return stmt.Keyword.Position
+	case *FunctionStmt:
+		if stmt.Keyword.Lexeme == "" {
+			return stmt.Name.Position
+		}
+
+		return stmt.Keyword.Position
+	case *ClassStmt:
+		return stmt.Keyword.Position
+	case *ReturnStmt:
+		return stmt.Keyword.Position
+	case *BreakStmt:
+		return stmt.Keyword.Position
+	case *ContinueStmt:
+		return stmt.Keyword.Position
+	case *TryStmt:
+		return stmt.Keyword.Position
+	case *ThrowStmt:
+		return stmt.Keyword.Position
+	case *ImportStmt:
+		return stmt.Keyword.Position
+	case *ExportStmt:
+		return stmt.Keyword.Position
+	case *TestStmt:
+		return stmt.Keyword.Position
+	}
+
+	return Position{}
+}
+
+// ExprStart returns the position of the leftmost token an expression keeps.
+func ExprStart(expr Expr) Position {
+	switch expr := expr.(type) {
+	case *BinaryExpr:
+		return ExprStart(expr.Left)
+	case *LogicalExpr:
+		return ExprStart(expr.Left)
+	case *UnaryExpr:
+		return expr.Operator.Position
+	case *GroupingExpr:
+		return ExprStart(expr.Expr)
+	case *LiteralExpr:
+		return expr.Token.Position
+	case *VarExpr:
+		return expr.Name.Position
+	case *AssignExpr:
+		return expr.Name.Position
+	case *CallExpr:
+		return ExprStart(expr.Callee)
+	case *ArrayExpr:
+		return expr.Token.Position
+	case *IndexExpr:
+		return ExprStart(expr.Array)
+	case *SetIndexExpr:
+		return ExprStart(expr.Array)
+	case *ObjectExpr:
+		return expr.Token.Position
+	case *GetExpr:
+		return ExprStart(expr.Object)
+	case *SetExpr:
+		return ExprStart(expr.Object)
+	case *ThisExpr:
+		return expr.Keyword.Position
+	case *FunctionExpr:
+		if len(expr.Declaration.Parameters) > 0 && expr.Declaration.Name.TokenType == ARROW {
+			return expr.Declaration.Parameters[0].Position
+		}
+
+		return expr.Declaration.Name.Position
+	}
+
+	return Position{}
+}
+
+// exprEnd returns the position of the rightmost token an expression keeps.
+func exprEnd(expr Expr) Position {
+	switch expr := expr.(type) {
+	case *BinaryExpr:
+		return exprEnd(expr.Right)
+	case *LogicalExpr:
+		return exprEnd(expr.Right)
+	case *UnaryExpr:
+		return exprEnd(expr.Right)
+	case *GroupingExpr:
+		return exprEnd(expr.Expr)
+	case *LiteralExpr:
+		return expr.Token.Position
+	case *VarExpr:
+		return expr.Name.Position
+	case *AssignExpr:
+		return exprEnd(expr.Value)
+	case *CallExpr:
+		return expr.Token.Position
+	case *ArrayExpr:
+		return expr.End.Position
+	case *IndexExpr:
+		return expr.Token.Position
+	case *SetIndexExpr:
+		return exprEnd(expr.Value)
+	case *ObjectExpr:
+		return expr.End.Position
+	case *GetExpr:
+		return expr.Name.Position
+	case *SetExpr:
+		return exprEnd(expr.Value)
+	case *ThisExpr:
+		return expr.Keyword.Position
+	case *FunctionExpr:
+		fn := expr.Declaration
+
+		// An arrow function returning an expression ends with it.
+		if fn.End.Lexeme == "" && len(fn.Body) == 1 {
+			if ret, ok := fn.Body[0].(*ReturnStmt); ok && ret.Value != nil {
+				return exprEnd(ret.Value)
+			}
+		}
+
+		return fn.End.Position
+	}
+
+	return Position{}
+}
@@ -0,0 +1,52 @@
+package ast
+
+import "strings"
+
+// Source is the text a file was scanned from, it is shared by all tokens of that file
+// so errors can quote the offending line.
+type Source struct {
+	Text string
+}
+
+func NewSource(text string) *Source {
+	return &Source{Text: text}
+}
+
+// Position locates a token in its source. Column is 1-based and counts bytes, Offset is
+// the byte offset of the first character of the token. Tokens made by the parser or the
+// interpreter have no source.
+type Position struct {
+	Line   int
+	Column int
+	Offset int
+	Source *Source
+}
+
+// SourceLine returns the number and text of the line containing the position, and the
+// byte offset of the position within that line.
+func (p Position) SourceLine() (number int, line string, column int, ok bool) {
+	if p.Source == nil || p.Offset < 0 || p.Offset > len(p.Source.Text) {
+		return 0, "", 0, false
+	}
+
+	text := p.Source.Text
+	offset := p.Offset
+
+	// The end of file sits after the trailing newline, point at the end of the last line instead.
+	if offset == len(text) && offset > 0 && text[offset-1] == '\n' {
+		offset--
+	}
+
+	start := strings.LastIndexByte(text[:offset], '\n') + 1
+
+	end := strings.IndexByte(text[offset:], '\n')
+	if end < 0 {
+		end = len(text)
+	} else {
+		end += offset
+	}
+
+	number = strings.Count(text[:start], "\n") + 1
+
+	return number, strings.TrimSuffix(text[start:end], "\r"), offset - start, true
+}
@@ -0,0 +1,290 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Printer renders statements and expressions as parenthesized S-expressions,
+// e.g. "(var a (+ 1.0 2.0))". It is used to inspect parsed programs.
+type Printer struct{}
+
+func NewPrinter() *Printer {
+	return &Printer{}
+}
+
+func (p *Printer) PrintStmt(stmt Stmt) string {
+	var sb strings.Builder
+
+	// The printer never fails, the error is part of the visitor signature only.
+	_ = stmt.Accept(&stmtPrinter{printer: p, sb: &sb})
+
+	return sb.String()
+}
+
+func (p *Printer) PrintExpr(expr Expr) string {
+	value, _ := expr.Accept(p)
+
+	return value.(string)
+}
+
+func (p *Printer) parenthesize(name string, parts ...string) string {
+	return "(" + strings.Join(append([]string{name}, parts...), " ") + ")"
+}
+
+func (p *Printer) exprs(exprs []Expr) []string {
+	parts := make([]string, 0, len(exprs))
+
+	for _, expr := range exprs {
+		parts = append(parts, p.PrintExpr(expr))
+	}
+
+	return parts
+}
+
+func (p *Printer) stmts(stmts []Stmt) []string {
+	parts := make([]string, 0, len(stmts))
+
+	for _, stmt := range stmts {
+		parts = append(parts, p.PrintStmt(stmt))
+	}
+
+	return parts
+}
+
+func (p *Printer) tokens(tokens []Token) string {
+	names := make([]string, 0, len(tokens))
+
+	for _, token := range tokens {
+		names = append(names, token.Lexeme)
+	}
+
+	return "(" + strings.Join(names, " ") + ")"
+}
+
+func (p *Printer) function(keyword string, fn *FunctionStmt) string {
+	parts := []string{}
+
+	if fn.Name.Lexeme != "" {
+		parts = append(parts, fn.Name.Lexeme)
+	}
+
+	parts = append(parts, p.tokens(fn.Parameters))
+	parts = append(parts, p.stmts(fn.Body)...)
+
+	return p.parenthesize(keyword, parts...)
+}
+
+func (p *Printer) VisitBinaryExpr(expr *BinaryExpr) (any, error) {
+	return p.parenthesize(expr.Operator.Lexeme, p.PrintExpr(expr.Left), p.PrintExpr(expr.Right)), nil
+}
+
+func (p *Printer) VisitLiteralExpr(expr *LiteralExpr) (any, error) {
+	if s, ok := expr.Value.(string); ok {
+		return fmt.Sprintf("%q", s), nil
+	}
+
+	return expr.String(), nil
+}
+
+func (p *Printer) VisitGroupingExpr(expr *GroupingExpr) (any, error) {
+	return p.parenthesize("group", p.PrintExpr(expr.Expr)), nil
+}
+
+func (p *Printer) VisitUnaryExpr(expr *UnaryExpr) (any, error) {
+	return p.parenthesize(expr.Operator.Lexeme, p.PrintExpr(expr.Right)), nil
+}
+
+func (p *Printer) VisitVarExpr(expr *VarExpr) (any, error) {
+	return expr.Name.Lexeme, nil
+}
+
+func (p *Printer) VisitAssignExpr(expr *AssignExpr) (any, error) {
+	return p.parenthesize("assign", expr.Name.Lexeme, p.PrintExpr(expr.Value)), nil
+}
+
+func (p *Printer) VisitLogicalExpr(expr *LogicalExpr) (any, error) {
+	return p.parenthesize(expr.Op.Lexeme, p.PrintExpr(expr.Left), p.PrintExpr(expr.Right)), nil
+}
+
+func (p *Printer) VisitCallExpr(expr *CallExpr) (any, error) {
+	return p.parenthesize("call", append([]string{p.PrintExpr(expr.Callee)}, p.exprs(expr.Args)...)...), nil
+}
+
+func (p *Printer) VisitArrayExpr(expr *ArrayExpr) (any, error) {
+	return p.parenthesize("array", p.exprs(expr.Items)...), nil
+}
+
+func (p *Printer) VisitIndexExpr(expr *IndexExpr) (any, error) {
+	return p.parenthesize("index", p.PrintExpr(expr.Array), p.PrintExpr(expr.Index)), nil
+}
+
+func (p *Printer) VisitSetIndexExpr(expr *SetIndexExpr) (any, error) {
+	return p.parenthesize("set-index", p.PrintExpr(expr.Array), p.PrintExpr(expr.Index), p.PrintExpr(expr.Value)), nil
+}
+
+func (p *Printer) VisitObjectExpr(expr *ObjectExpr) (any, error) {
+	parts := make([]string, 0, len(expr.Pairs))
+	for _, pair := range expr.Pairs {
+		parts = append(parts, p.parenthesize(pair.Key.Lexeme, p.PrintExpr(pair.Value)))
+	}
+
+	return p.parenthesize("object", parts...), nil
+}
+
+func (p *Printer) VisitThisExpr(_ *ThisExpr) (any, error) {
+	return "this", nil
+}
+
+func (p *Printer) VisitGetExpr(expr *GetExpr) (any, error) {
+	return p.parenthesize("get", p.PrintExpr(expr.Object), expr.Name.Lexeme), nil
+}
+
+func (p *Printer) VisitSetExpr(expr *SetExpr) (any, error) {
+	return p.parenthesize("set", p.PrintExpr(expr.Object), expr.Name.Lexeme, p.PrintExpr(expr.Value)), nil
+}
+
+func (p *Printer) VisitFunctionExpr(expr *FunctionExpr) (any, error) {
+	return p.function("lambda", expr.Declaration), nil
+}
+
+// stmtPrinter writes statements into a builder, since statement visitors only return errors.
+type stmtPrinter struct {
+	printer *Printer
+	sb      *strings.Builder
+}
+
+func (s *stmtPrinter) write(name string, parts ...string) error {
+	s.sb.WriteString(s.printer.parenthesize(name, parts...))
+	return nil
+}
+
+func (s *stmtPrinter) VisitPrintStmt(stmt *PrintStmt) error {
+	return s.write("print", s.printer.PrintExpr(stmt.Expr))
+}
+
+func (s *stmtPrinter) VisitExprStmt(stmt *ExprStmt) error {
+	return s.write("expr", s.printer.PrintExpr(stmt.Expr))
+}
+
+func (s *stmtPrinter) VisitVarStmt(stmt *VarStmt) error {
+	if stmt.Initializer == nil {
+		return s.write("var", stmt.Name.Lexeme)
+	}
+
+	return s.write("var", stmt.Name.Lexeme, s.printer.PrintExpr(stmt.Initializer))
+}
+
+func (s *stmtPrinter) VisitBlockStmt(stmt *BlockStmt) error {
+	return s.write("block", s.printer.stmts(stmt.Stmts)...)
+}
+
+func (s *stmtPrinter) VisitIfStmt(stmt *IfStmt) error {
+	parts := []string{s.printer.PrintExpr(stmt.Condition), s.printer.PrintStmt(stmt.Then)}
+
+	if stmt.El != nil {
+		parts = append(parts, s.printer.PrintStmt(stmt.El))
+	}
+
+	return s.write("if", parts...)
+}
+
+func (s *stmtPrinter) VisitWhileStmt(stmt *WhileStmt) error {
+	parts := []string{}
+
+	if stmt.Label.Lexeme != "" {
+		parts = append(parts, stmt.Label.Lexeme+":")
+	}
+
+	parts = append(parts, s.printer.PrintExpr(stmt.Condition), s.printer.PrintStmt(stmt.Body))
+
+	if stmt.Increment != nil {
+		parts = append(parts, s.printer.PrintExpr(stmt.Increment))
+	}
+
+	return s.write("while", parts...)
+}
+
+func (s *stmtPrinter) VisitFunctionStmt(stmt *FunctionStmt) error {
+	s.sb.WriteString(s.printer.function("fun", stmt))
+	return nil
+}
+
+func (s *stmtPrinter) VisitReturnStmt(stmt *ReturnStmt) error {
+	if stmt.Value == nil {
+		return s.write("return")
+	}
+
+	return s.write("return", s.printer.PrintExpr(stmt.Value))
+}
+
+func (s *stmtPrinter) VisitClassStmt(stmt *ClassStmt) error {
+	parts := []string{stmt.Name.Lexeme}
+
+	for _, method := range stmt.Methods {
+		parts = append(parts, s.printer.function("method", method))
+	}
+
+	return s.write("class", parts...)
+}
+
+func (s *stmtPrinter) VisitBreakStmt(stmt *BreakStmt) error {
+	if stmt.Label.Lexeme != "" {
+		return s.write("break", stmt.Label.Lexeme)
+	}
+
+	return s.write("break")
+}
+
+func (s *stmtPrinter) VisitContinueStmt(stmt *ContinueStmt) error {
+	if stmt.Label.Lexeme != "" {
+		return s.write("continue", stmt.Label.Lexeme)
+	}
+
+	return s.write("continue")
+}
+
+func (s *stmtPrinter) VisitTryStmt(stmt *TryStmt) error {
+	parts := []string{s.printer.parenthesize("block", s.printer.stmts(stmt.Body)...)}
+
+	if stmt.CatchBody != nil {
+		catch := []string{}
+		if stmt.CatchName.Lexeme != "" {
+			catch = append(catch, stmt.CatchName.Lexeme)
+		}
+
+		parts = append(parts, s.printer.parenthesize("catch", append(catch, s.printer.stmts(stmt.CatchBody)...)...))
+	}
+
+	if stmt.FinallyBody != nil {
+		parts = append(parts, s.printer.parenthesize("finally", s.printer.stmts(stmt.FinallyBody)...))
+	}
+
+	return s.write("try", parts...)
+}
+
+func (s *stmtPrinter) VisitThrowStmt(stmt *ThrowStmt) error {
+	return s.write("throw", s.printer.PrintExpr(stmt.Value))
+}
+
+func (s *stmtPrinter) VisitImportStmt(stmt *ImportStmt) error {
+	parts := []string{fmt.Sprintf("%q", stmt.Path.Literal)}
+
+	if stmt.Alias.Lexeme != "" {
+		parts = append(parts, "as", stmt.Alias.Lexeme)
+	}
+
+	if len(stmt.Names) > 0 {
+		parts = append(parts, s.printer.tokens(stmt.Names))
+	}
+
+	return s.write("import", parts...)
+}
+
+func (s *stmtPrinter) VisitExportStmt(stmt *ExportStmt) error {
+	return s.write("export", s.printer.PrintStmt(stmt.Declaration))
+}
+
+func (s *stmtPrinter) VisitTestStmt(stmt *TestStmt) error {
+	return s.write("test", append([]string{fmt.Sprintf("%q", stmt.Name.Literal)}, s.printer.stmts(stmt.Function.Body)...)...)
+}
@@ -9,15 +9,24 @@ type StmtVisitor interface {
 	VisitWhileStmt(whileStmt *WhileStmt) error
 	VisitFunctionStmt(functionStmt *FunctionStmt) error
 	VisitReturnStmt(returnStmt *ReturnStmt) error
+	VisitClassStmt(classStmt *ClassStmt) error
+	VisitBreakStmt(breakStmt *BreakStmt) error
+	VisitContinueStmt(continueStmt *ContinueStmt) error
+	VisitTryStmt(tryStmt *TryStmt) error
+	VisitThrowStmt(throwStmt *ThrowStmt) error
+	VisitImportStmt(importStmt *ImportStmt) error
+	VisitExportStmt(exportStmt *ExportStmt) error
+	VisitTestStmt(testStmt *TestStmt) error
 }
 
 type VarStmt struct {
+	Keyword     Token
 	Initializer Expr
 	Name        Token
 }
 
-func NewVarStmt(initializer Expr, name Token) Stmt {
-	return &VarStmt{Initializer: initializer, Name: name}
+func NewVarStmt(keyword Token, initializer Expr, name Token) Stmt {
+	return &VarStmt{Keyword: keyword, Initializer: initializer, Name: name}
 }
 
 type ReturnStmt struct {
@@ -30,11 +39,12 @@ func NewReturnStmt(value Expr, keyword Token) Stmt {
 }
 
 type PrintStmt struct {
-	Expr Expr
+	Keyword Token
+	Expr    Expr
 }
 
-func NewPrintStmt(expr Expr) Stmt {
-	return &PrintStmt{Expr: expr}
+func NewPrintStmt(keyword Token, expr Expr) Stmt {
+	return &PrintStmt{Keyword: keyword, Expr: expr}
 }
 
 type ExprStmt struct {
@@ -45,41 +55,158 @@ func NewExprStmt(expr Expr) Stmt {
 	return &ExprStmt{Expr: expr}
 }
 
+// BlockStmt is a block in braces. The block the parser wraps around a for loop with an
+// initializer has no braces, its tokens have empty lexemes.
 type BlockStmt struct {
 	Stmts []Stmt
+	// Token is the opening brace and End the closing one.
+	Token Token
+	End   Token
 }
 
-func NewBlockStmt(stmts []Stmt) Stmt {
-	return &BlockStmt{Stmts: stmts}
+func NewBlockStmt(token Token, stmts []Stmt, end Token) Stmt {
+	return &BlockStmt{Token: token, Stmts: stmts, End: end}
 }
 
+// IfStmt has a nil El when there is no else branch, ElseKeyword then has an empty lexeme.
 type IfStmt struct {
-	Condition Expr
-	Then      Stmt
-	El        Stmt
+	Keyword     Token
+	Condition   Expr
+	Then        Stmt
+	El          Stmt
+	ElseKeyword Token
 }
 
-func NewIfStmt(condition Expr, then Stmt, el Stmt) Stmt {
-	return &IfStmt{Condition: condition, Then: then, El: el}
+func NewIfStmt(keyword Token, condition Expr, then Stmt, el Stmt, elseKeyword Token) Stmt {
+	return &IfStmt{Keyword: keyword, Condition: condition, Then: then, El: el, ElseKeyword: elseKeyword}
 }
 
+// WhileStmt is also the desugared form of a for loop, Increment holds its increment clause
+// so that 'continue' still runs it. Label has an empty lexeme when the loop is unlabeled.
 type WhileStmt struct {
+	// Keyword is the 'while' or 'for' the loop was written with.
+	Keyword   Token
 	Condition Expr
 	Body      Stmt
+	Increment Expr
+	Label     Token
+}
+
+func NewWhileStmt(keyword Token, condition Expr, body Stmt, increment Expr, label Token) Stmt {
+	return &WhileStmt{Keyword: keyword, Condition: condition, Body: body, Increment: increment, Label: label}
+}
+
+type BreakStmt struct {
+	Keyword Token
+	Label   Token
 }
 
-func NewWhileStmt(condition Expr, body Stmt) Stmt {
-	return &WhileStmt{Condition: condition, Body: body}
+func NewBreakStmt(keyword Token, label Token) Stmt {
+	return &BreakStmt{Keyword: keyword, Label: label}
+}
+
+type ContinueStmt struct {
+	Keyword Token
+	Label   Token
+}
+
+func NewContinueStmt(keyword Token, label Token) Stmt {
+	return &ContinueStmt{Keyword: keyword, Label: label}
 }
 
 type FunctionStmt struct {
-	Name       Token
+	// Keyword is the 'fun' of a declaration, it has an empty lexeme for methods and
+	// anonymous functions.
+	Keyword Token
+	Name    Token
+	// Open and Close are the parentheses around the parameters, they have empty lexemes
+	// for test blocks.
+	Open       Token
 	Parameters []Token
+	Close      Token
 	Body       []Stmt
+	// End is the brace closing the body, it has an empty lexeme for arrow functions
+	// returning an expression.
+	End Token
 }
 
-func NewFunctionStmt(name Token, parameters []Token, body []Stmt) Stmt {
-	return &FunctionStmt{Name: name, Parameters: parameters, Body: body}
+func NewFunctionStmt(keyword Token, name Token, open Token, parameters []Token, close Token, body []Stmt, end Token) Stmt {
+	return &FunctionStmt{Keyword: keyword, Name: name, Open: open, Parameters: parameters, Close: close, Body: body, End: end}
+}
+
+type ClassStmt struct {
+	Keyword Token
+	Name    Token
+	Methods []*FunctionStmt
+	// End is the brace closing the class body.
+	End Token
+}
+
+func NewClassStmt(keyword Token, name Token, methods []*FunctionStmt, end Token) Stmt {
+	return &ClassStmt{Keyword: keyword, Name: name, Methods: methods, End: end}
+}
+
+// TryStmt has a nil CatchBody when there is no catch clause and a nil FinallyBody
+// when there is no finally clause. CatchName has an empty lexeme when the caught
+// value is not bound to a variable. The End tokens are the braces closing each body,
+// and the catch and finally keywords have empty lexemes when their clause is missing.
+type TryStmt struct {
+	Keyword        Token
+	Body           []Stmt
+	CatchKeyword   Token
+	CatchName      Token
+	CatchBody      []Stmt
+	FinallyKeyword Token
+	FinallyBody    []Stmt
+	BodyEnd        Token
+	CatchEnd       Token
+	FinallyEnd     Token
+}
+
+type ThrowStmt struct {
+	Keyword Token
+	Value   Expr
+}
+
+func NewThrowStmt(keyword Token, value Expr) Stmt {
+	return &ThrowStmt{Keyword: keyword, Value: value}
+}
+
+// ImportStmt binds a whole module to Alias (import "lib.rn" as lib;) or only the
+// listed Names (import { a, b } from "lib.rn";). Alias has an empty lexeme and Names
+// is empty when the module is imported only for its side effects.
+type ImportStmt struct {
+	Keyword Token
+	Path    Token
+	Alias   Token
+	Names   []Token
+}
+
+func NewImportStmt(keyword Token, path Token, alias Token, names []Token) Stmt {
+	return &ImportStmt{Keyword: keyword, Path: path, Alias: alias, Names: names}
+}
+
+// ExportStmt marks a top-level declaration as visible to modules importing it.
+type ExportStmt struct {
+	Keyword     Token
+	Name        Token
+	Declaration Stmt
+}
+
+func NewExportStmt(keyword Token, name Token, declaration Stmt) Stmt {
+	return &ExportStmt{Keyword: keyword, Name: name, Declaration: declaration}
+}
+
+// TestStmt is a top-level test block: test "description" { ... }. Its body is run as a
+// function without parameters by the test command and skipped otherwise.
+type TestStmt struct {
+	Keyword  Token
+	Name     Token
+	Function *FunctionStmt
+}
+
+func NewTestStmt(keyword Token, name Token, function *FunctionStmt) Stmt {
+	return &TestStmt{Keyword: keyword, Name: name, Function: function}
 }
 
 type Stmt interface {
@@ -117,3 +244,35 @@ func (n *WhileStmt) Accept(v StmtVisitor) error {
 func (n *ReturnStmt) Accept(v StmtVisitor) error {
 	return v.VisitReturnStmt(n)
 }
+
+func (n *ClassStmt) Accept(v StmtVisitor) error {
+	return v.VisitClassStmt(n)
+}
+
+func (n *BreakStmt) Accept(v StmtVisitor) error {
+	return v.VisitBreakStmt(n)
+}
+
+func (n *ContinueStmt) Accept(v StmtVisitor) error {
+	return v.VisitContinueStmt(n)
+}
+
+func (n *TryStmt) Accept(v StmtVisitor) error {
+	return v.VisitTryStmt(n)
+}
+
+func (n *ThrowStmt) Accept(v StmtVisitor) error {
+	return v.VisitThrowStmt(n)
+}
+
+func (n *ImportStmt) Accept(v StmtVisitor) error {
+	return v.VisitImportStmt(n)
+}
+
+func (n *ExportStmt) Accept(v StmtVisitor) error {
+	return v.VisitExportStmt(n)
+}
+
+func (n *TestStmt) Accept(v StmtVisitor) error {
+	return v.VisitTestStmt(n)
+}
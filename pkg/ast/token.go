@@ -8,11 +8,21 @@ type Token struct {
 	TokenType TokenType
 	Lexeme    string
 	Literal   string
-	Line      int
+	Position
+}
+
+// Comment is a line comment. The scanner keeps comments aside as trivia, the parser
+// never sees them, so that tools such as the formatter can put them back in place.
+type Comment struct {
+	// Text is the comment from its '//' to the end of the line, line break excluded.
+	Text string
+	// Trailing is true when the comment follows code on the same line.
+	Trailing bool
+	Position
 }
 
 func NewToken(tokenType TokenType, lexeme string, literal string, line int) Token {
-	return Token{TokenType: tokenType, Lexeme: lexeme, Literal: literal, Line: line}
+	return Token{TokenType: tokenType, Lexeme: lexeme, Literal: literal, Position: Position{Line: line}}
 }
 
 func (token Token) String() string {
@@ -47,6 +57,16 @@ const (
 	RETURN
 	TRUE
 	WHILE
+	CLASS
+	THIS
+	BREAK
+	CONTINUE
+	TRY
+	CATCH
+	FINALLY
+	THROW
+	IMPORT
+	EXPORT
 
 	// Single tokens
 	COLON
@@ -70,6 +90,7 @@ const (
 	BANG_EQUAL
 	EQUAL
 	EQUAL_EQUAL
+	ARROW
 	LESS
 	LESS_EQUAL
 	GREATER
@@ -83,19 +104,29 @@ const (
 )
 
 var Keywords = map[string]TokenType{
-	"var":    VAR,
-	"and":    AND,
-	"or":     OR,
-	"else":   ELSE,
-	"false":  FALSE,
-	"fun":    FUN,
-	"for":    FOR,
-	"if":     IF,
-	"nil":    NIL,
-	"print":  PRINT,
-	"return": RETURN,
-	"true":   TRUE,
-	"while":  WHILE,
+	"var":      VAR,
+	"and":      AND,
+	"or":       OR,
+	"else":     ELSE,
+	"false":    FALSE,
+	"fun":      FUN,
+	"for":      FOR,
+	"if":       IF,
+	"nil":      NIL,
+	"print":    PRINT,
+	"return":   RETURN,
+	"true":     TRUE,
+	"while":    WHILE,
+	"class":    CLASS,
+	"this":     THIS,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"throw":    THROW,
+	"import":   IMPORT,
+	"export":   EXPORT,
 }
 
 func (tokenType TokenType) String() string {
@@ -157,6 +188,26 @@ func (tokenType TokenType) String() string {
 		return "WHILE"
 	case OR:
 		return "OR"
+	case CLASS:
+		return "CLASS"
+	case THIS:
+		return "THIS"
+	case BREAK:
+		return "BREAK"
+	case CONTINUE:
+		return "CONTINUE"
+	case TRY:
+		return "TRY"
+	case CATCH:
+		return "CATCH"
+	case FINALLY:
+		return "FINALLY"
+	case THROW:
+		return "THROW"
+	case IMPORT:
+		return "IMPORT"
+	case EXPORT:
+		return "EXPORT"
 	case LEFT_PAREN:
 		return "LEFT_PAREN"
 	case RIGHT_PAREN:
@@ -175,6 +226,8 @@ func (tokenType TokenType) String() string {
 		return "EQUAL"
 	case EQUAL_EQUAL:
 		return "EQUAL_EQUAL"
+	case ARROW:
+		return "ARROW"
 	case BANG:
 		return "BANG"
 	case BANG_EQUAL:
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"rune/pkg/ast"
 	"rune/pkg/errors"
+	"slices"
 )
 
 type AppendCallable struct{}
@@ -18,11 +19,11 @@ func (c *AppendCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (an
 	}
 
 	switch v := args[0].(type) {
-	case []any:
-		v = append(v, args[1:]...)
-		return v, nil
+	case *Array:
+		// The items are copied, pushing to the result never changes the array.
+		return NewArray(slices.Concat(v.Items(), args[1:])), nil
 	default:
-		return 0, errors.NewRuntimeError(token, fmt.Sprintf("Can only append to arrays, got %T", args[0]))
+		return 0, errors.NewRuntimeError(token, fmt.Sprintf("Can only append to arrays, got %s", typeName(args[0])))
 	}
 }
 
@@ -30,6 +31,14 @@ func (c *AppendCallable) Arity() int {
 	return -1
 }
 
+func (c *AppendCallable) Name() string {
+	return "append"
+}
+
+func (c *AppendCallable) Doc() string {
+	return "append(array, values...)\n\nReturns a new array with the values added at the end of the array, which is left unchanged. Use push to add them in place."
+}
+
 func (c *AppendCallable) String() string {
 	return "<native fn>"
 }
@@ -0,0 +1,75 @@
+package callable
+
+import (
+	"fmt"
+	"slices"
+
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// Array is the value of an array literal. Arrays are shared by reference, items pushed
+// or removed through one variable are seen through every other holding the array.
+type Array struct {
+	items []any
+}
+
+func NewArray(items []any) *Array {
+	return &Array{items: items}
+}
+
+// Items returns the items in order. The slice must not be modified.
+func (a *Array) Items() []any {
+	return a.items
+}
+
+func (a *Array) Len() int {
+	return len(a.items)
+}
+
+// Get returns the item at an index, which must be in bounds.
+func (a *Array) Get(index int) any {
+	return a.items[index]
+}
+
+// Set replaces the item at an index, which must be in bounds.
+func (a *Array) Set(index int, value any) {
+	a.items[index] = value
+}
+
+// Push adds values at the end.
+func (a *Array) Push(values ...any) {
+	a.items = append(a.items, values...)
+}
+
+// Insert adds a value before the item at an index, or at the end for the length.
+func (a *Array) Insert(index int, value any) {
+	a.items = slices.Insert(a.items, index, value)
+}
+
+// RemoveAt removes the item at an index, which must be in bounds, and returns it.
+func (a *Array) RemoveAt(index int) any {
+	value := a.items[index]
+	a.items = slices.Delete(a.items, index, index+1)
+
+	return value
+}
+
+func (a *Array) String() string {
+	return Stringify(a)
+}
+
+// arrayIndex converts the index argument of a native to an int. Indexes past the last
+// item are only valid when end is true, to add an item at the end.
+func arrayIndex(array *Array, index any, end bool, token ast.Token) (int, error) {
+	i, ok := index.(float64)
+	if !ok {
+		return 0, errors.NewRuntimeError(token, "Array index must be a number.")
+	}
+
+	if int(i) < 0 || int(i) > array.Len() || int(i) == array.Len() && !end {
+		return 0, errors.NewRuntimeError(token, fmt.Sprintf("Index out of bounds: %v of %v", int(i), array.Len()))
+	}
+
+	return int(i), nil
+}
@@ -0,0 +1,174 @@
+package callable
+
+import (
+	"fmt"
+
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// AssertCallable fails with an AssertionError unless its condition is true. An optional
+// second argument replaces the message.
+type AssertCallable struct{}
+
+func NewAssertCallable() Callable {
+	return &AssertCallable{}
+}
+
+func (c *AssertCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Expected 1 or 2 arguments but got %d.", len(args)))
+	}
+
+	condition, ok := args[0].(bool)
+	if !ok {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("assert() expects a boolean condition, got %s.", typeName(args[0])))
+	}
+
+	if !condition {
+		return nil, errors.NewAssertionError(token, assertionMessage(args, 1, "Assertion failed."))
+	}
+
+	return nil, nil
+}
+
+func (c *AssertCallable) Arity() int {
+	return -1
+}
+
+func (c *AssertCallable) Name() string {
+	return "assert"
+}
+
+func (c *AssertCallable) Doc() string {
+	return "assert(condition, message?)\n\nRaises an AssertionError unless the boolean condition is true."
+}
+
+func (c *AssertCallable) String() string {
+	return "<native fn>"
+}
+
+// AssertEqualCallable fails with an AssertionError showing both values unless its first
+// argument equals the expected second one. An optional third argument is prepended to
+// the message.
+type AssertEqualCallable struct {
+	equal func(a any, b any) bool
+}
+
+// NewAssertEqualCallable compares values with equal, the equality of ==, which is
+// passed in since helpers imports this package.
+func NewAssertEqualCallable(equal func(a any, b any) bool) Callable {
+	return &AssertEqualCallable{equal: equal}
+}
+
+func (c *AssertEqualCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Expected 2 or 3 arguments but got %d.", len(args)))
+	}
+
+	actual, expected := args[0], args[1]
+	if c.equal(actual, expected) {
+		return nil, nil
+	}
+
+	if len(args) == 3 {
+		return nil, errors.NewAssertionError(token, fmt.Sprintf("%s: expected %s but got %s.", assertionMessage(args, 2, ""), Inspect(expected), Inspect(actual)))
+	}
+
+	return nil, errors.NewAssertionError(token, fmt.Sprintf("Expected %s but got %s.", Inspect(expected), Inspect(actual)))
+}
+
+func (c *AssertEqualCallable) Arity() int {
+	return -1
+}
+
+func (c *AssertEqualCallable) Name() string {
+	return "assertEqual"
+}
+
+func (c *AssertEqualCallable) Doc() string {
+	return "assertEqual(actual, expected, message?)\n\nRaises an AssertionError showing both values unless they are equal. Arrays and objects are compared by their items."
+}
+
+func (c *AssertEqualCallable) String() string {
+	return "<native fn>"
+}
+
+// AssertThrowsCallable calls a function without arguments and fails with an
+// AssertionError unless it throws or raises an error a catch clause could handle. An
+// optional second argument is the expected message of the error.
+type AssertThrowsCallable struct{}
+
+func NewAssertThrowsCallable() Callable {
+	return &AssertThrowsCallable{}
+}
+
+func (c *AssertThrowsCallable) Call(executeBlock ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Expected 1 or 2 arguments but got %d.", len(args)))
+	}
+
+	fn, ok := args[0].(Callable)
+	if !ok {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("assertThrows() expects a function, got %s.", typeName(args[0])))
+	}
+
+	if fn.Arity() > 0 {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("assertThrows() expects a function without parameters, got one with %d.", fn.Arity()))
+	}
+
+	_, err := fn.Call(executeBlock, nil, token)
+
+	var message string
+	switch e := err.(type) {
+	case nil:
+		return nil, errors.NewAssertionError(token, "Expected the function to throw.")
+	case *errors.ThrownError:
+		message = e.Message()
+	case errors.RuntimeError:
+		if !e.Catchable() {
+			return nil, err
+		}
+
+		message = e.Message()
+	default:
+		return nil, err
+	}
+
+	if len(args) == 2 {
+		if expected, ok := args[1].(string); !ok || expected != message {
+			return nil, errors.NewAssertionError(token, fmt.Sprintf("Expected the function to throw %s but it threw %q.", Inspect(args[1]), message))
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *AssertThrowsCallable) Arity() int {
+	return -1
+}
+
+func (c *AssertThrowsCallable) Name() string {
+	return "assertThrows"
+}
+
+func (c *AssertThrowsCallable) Doc() string {
+	return "assertThrows(fn, message?)\n\nCalls fn and raises an AssertionError unless it throws, optionally with the given message."
+}
+
+func (c *AssertThrowsCallable) String() string {
+	return "<native fn>"
+}
+
+// assertionMessage returns the custom message passed as argument i, or the default one.
+func assertionMessage(args []any, i int, message string) string {
+	if len(args) > i {
+		if custom, ok := args[i].(string); ok {
+			return custom
+		}
+
+		return Inspect(args[i])
+	}
+
+	return message
+}
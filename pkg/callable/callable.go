@@ -3,6 +3,7 @@ package callable
 import (
 	"rune/pkg/ast"
 	"rune/pkg/environment"
+	"rune/pkg/sandbox"
 )
 
 const MaxArity = 8
@@ -26,3 +27,32 @@ type Callable interface {
 	Call(executeBlock ExecuteBlockFn, args []any, token ast.Token) (any, error)
 	Arity() int
 }
+
+// Named is implemented by callables that have a name to show in stack traces.
+type Named interface {
+	Name() string
+}
+
+// Documented is implemented by native functions that describe themselves to tools such
+// as the language server. The first line of the doc is the signature.
+type Documented interface {
+	Doc() string
+}
+
+// CallFn calls a function value the way a script calls it: permissions are checked,
+// limits counted and the call is a frame of stack traces.
+type CallFn func(fn Callable, args []any, token ast.Token) (any, error)
+
+// CallsBack is implemented by native functions that call the functions they are passed.
+// Engines run them with CallWith rather than Call, giving the function that calls
+// through the engine.
+type CallsBack interface {
+	CallWith(call CallFn, args []any, token ast.Token) (any, error)
+}
+
+// Guarded is implemented by native functions that access the network, files or the
+// environment. The interpreter only runs a call if the permissions it was granted
+// allow every request returned by Requires for the arguments of the call.
+type Guarded interface {
+	Requires(args []any) []sandbox.Request
+}
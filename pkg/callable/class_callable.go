@@ -0,0 +1,50 @@
+package callable
+
+import (
+	"rune/pkg/ast"
+)
+
+const InitializerName = "init"
+
+// ClassCallable is a callable that represents a class, calling it creates a new instance.
+type ClassCallable struct {
+	Name    string
+	methods map[string]*FunctionCallable
+}
+
+func NewClassCallable(name string, methods map[string]*FunctionCallable) *ClassCallable {
+	return &ClassCallable{
+		Name:    name,
+		methods: methods,
+	}
+}
+
+func (c *ClassCallable) FindMethod(name string) (*FunctionCallable, bool) {
+	method, ok := c.methods[name]
+
+	return method, ok
+}
+
+func (c *ClassCallable) Call(executeBlock ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	instance := NewInstance(c)
+
+	if initializer, ok := c.FindMethod(InitializerName); ok {
+		if _, err := initializer.Bind(instance).Call(executeBlock, args, token); err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+func (c *ClassCallable) Arity() int {
+	if initializer, ok := c.FindMethod(InitializerName); ok {
+		return initializer.Arity()
+	}
+
+	return 0
+}
+
+func (c *ClassCallable) String() string {
+	return c.Name
+}
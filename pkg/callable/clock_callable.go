@@ -20,6 +20,14 @@ func (c *ClockCallable) Arity() int {
 	return 0
 }
 
+func (c *ClockCallable) Name() string {
+	return "clock"
+}
+
+func (c *ClockCallable) Doc() string {
+	return "clock()\n\nReturns the current time in seconds since the Unix epoch."
+}
+
 func (c *ClockCallable) String() string {
 	return "<native fn>"
 }
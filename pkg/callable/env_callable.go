@@ -0,0 +1,57 @@
+package callable
+
+import (
+	"fmt"
+	"os"
+
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+	"rune/pkg/sandbox"
+)
+
+// EnvCallable is a callable that returns the value of an environment variable, or nil
+// if it is not set.
+type EnvCallable struct{}
+
+func NewEnvCallable() Callable {
+	return &EnvCallable{}
+}
+
+func (c *EnvCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("env() expects a variable name, got %s.", typeName(args[0])))
+	}
+
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+
+	return nil, nil
+}
+
+// Requires returns access to the variable.
+func (c *EnvCallable) Requires(args []any) []sandbox.Request {
+	name, ok := args[0].(string)
+	if !ok {
+		return nil
+	}
+
+	return []sandbox.Request{{Kind: sandbox.Env, Target: name}}
+}
+
+func (c *EnvCallable) Arity() int {
+	return 1
+}
+
+func (c *EnvCallable) Name() string {
+	return "env"
+}
+
+func (c *EnvCallable) Doc() string {
+	return "env(name)\n\nReturns the value of an environment variable, or nil if it is not set. Needs access to the variable."
+}
+
+func (c *EnvCallable) String() string {
+	return "<native fn>"
+}
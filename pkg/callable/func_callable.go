@@ -0,0 +1,40 @@
+package callable
+
+import (
+	"rune/pkg/ast"
+)
+
+// FuncCallable is a native function implemented by a Go function, it lets programs
+// that embed Rune expose their own functions to scripts.
+type FuncCallable struct {
+	name  string
+	arity int
+	fn    func(args []any) (any, error)
+}
+
+// NewFuncCallable wraps a Go function taking arity arguments, -1 for any number. An
+// error returned by fn becomes a runtime error at the call, which scripts can catch.
+func NewFuncCallable(name string, arity int, fn func(args []any) (any, error)) Callable {
+	return &FuncCallable{name: name, arity: arity, fn: fn}
+}
+
+func (c *FuncCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	result, err := c.fn(args)
+	if err != nil {
+		return nil, hostError(err, token)
+	}
+
+	return result, nil
+}
+
+func (c *FuncCallable) Arity() int {
+	return c.arity
+}
+
+func (c *FuncCallable) Name() string {
+	return c.name
+}
+
+func (c *FuncCallable) String() string {
+	return "<native fn>"
+}
@@ -6,43 +6,83 @@ import (
 	"rune/pkg/environment"
 )
 
+// thisSlot is the slot of "this" in the scope a method is bound to.
+const thisSlot = 0
+
 // FunctionCallable is a callable that represents a function.
 type FunctionCallable struct {
-	Declaration *ast.FunctionStmt
-	environment *environment.Environment
+	Declaration   *ast.FunctionStmt
+	environment   *environment.Environment
+	isInitializer bool
 }
 
-func NewFunctionCallable(declaration *ast.FunctionStmt, environment *environment.Environment) *FunctionCallable {
+func NewFunctionCallable(
+	declaration *ast.FunctionStmt,
+	environment *environment.Environment,
+	isInitializer bool,
+) *FunctionCallable {
 	return &FunctionCallable{
-		Declaration: declaration,
-		environment: environment,
+		Declaration:   declaration,
+		environment:   environment,
+		isInitializer: isInitializer,
 	}
 }
 
+// Bind returns a copy of the method whose closure has "this" set to the given instance.
+func (f *FunctionCallable) Bind(instance *Instance) *FunctionCallable {
+	env := environment.NewEnvironment(f.environment)
+	env.Define("this", instance)
+
+	return NewFunctionCallable(f.Declaration, env, f.isInitializer)
+}
+
 func (f *FunctionCallable) Call(executeBlock ExecuteBlockFn, args []any, _ ast.Token) (any, error) {
 	env := environment.NewEnvironment(f.environment)
 
 	for i, param := range f.Declaration.Parameters {
-		if len(args) <= i {
-			continue
+		var arg any
+		if i < len(args) {
+			arg = args[i]
 		}
 
-		env.Define(param.Lexeme, args[i])
+		env.Define(param.Lexeme, arg)
 	}
 
 	err := executeBlock(f.Declaration.Body, env)
 
 	if ret, isReturn := err.(*Return); isReturn {
+		if f.isInitializer {
+			return f.environment.GetAt(0, thisSlot), nil
+		}
+
 		return ret.value, nil
 	}
 
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+
+	// An initializer always returns the instance, even when called directly.
+	if f.isInitializer {
+		return f.environment.GetAt(0, thisSlot), nil
+	}
+
+	return nil, nil
 }
 
 func (f *FunctionCallable) Arity() int {
 	return len(f.Declaration.Parameters)
 }
 
+// Name returns the declared name of the function, or "anonymous" for function expressions.
+func (f *FunctionCallable) Name() string {
+	if f.Declaration.Name.Lexeme == "" {
+		return "anonymous"
+	}
+
+	return f.Declaration.Name.Lexeme
+}
+
 func (f *FunctionCallable) String() string {
-	return fmt.Sprintf("<fn %s>", f.Declaration.Name.Lexeme)
+	return fmt.Sprintf("<fn %s>", f.Name())
 }
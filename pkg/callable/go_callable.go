@@ -0,0 +1,592 @@
+package callable
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+var (
+	errorType    = reflect.TypeFor[error]()
+	callableType = reflect.TypeFor[Callable]()
+	objectType   = reflect.TypeFor[*Object]()
+	arrayType    = reflect.TypeFor[*Array]()
+)
+
+// GoCallable is a native function bound to a Go function by reflection. Arguments
+// are converted from Rune values to the parameter types of the function and its
+// result is converted back, see NewGoCallable.
+type GoCallable struct {
+	name string
+	fn   reflect.Value
+}
+
+// NewGoCallable binds a Go function, e.g. func(string, float64) (string, error), so
+// scripts can call it. The arity is the number of parameters, or any number for a
+// variadic function. Parameters can be numbers, strings, bools, slices, maps with
+// string keys, callables, any, and functions which call back into the script. Such a
+// function must return an error or a value and an error, the error of the script
+// function, and is only valid during the call. The bound function may return nothing,
+// a value, an error or a value and an error. Values are converted with ToValue,
+// structs become objects.
+func NewGoCallable(name string, fn any) (Callable, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, fmt.Errorf("cannot bind %s: expected a function, got %T", name, fn)
+	}
+
+	if err := checkFunc(v.Type()); err != nil {
+		return nil, fmt.Errorf("cannot bind %s: %w", name, err)
+	}
+
+	return &GoCallable{name: name, fn: v}, nil
+}
+
+// Call calls the Go function outside of an engine, script functions it calls back are
+// called directly. Engines use CallWith.
+func (c *GoCallable) Call(executeBlock ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	return c.CallWith(func(fn Callable, args []any, token ast.Token) (any, error) {
+		return fn.Call(executeBlock, args, token)
+	}, args, token)
+}
+
+// CallWith calls the Go function, the functions it calls back are called with call.
+func (c *GoCallable) CallWith(call CallFn, args []any, token ast.Token) (any, error) {
+	t := c.fn.Type()
+
+	if t.IsVariadic() && len(args) < t.NumIn()-1 {
+		return nil, errors.NewRuntimeError(
+			token,
+			fmt.Sprintf("Expected at least %d arguments but got %d.", t.NumIn()-1, len(args)),
+		)
+	}
+
+	in := make([]reflect.Value, len(args))
+
+	for i, arg := range args {
+		paramType := paramAt(t, i)
+
+		value, ok := fromValue(arg, paramType, call, token)
+		if !ok {
+			return nil, errors.NewRuntimeError(
+				token,
+				fmt.Sprintf("%s() expects %s as argument %d, got %s.", c.name, describe(paramType), i+1, typeName(arg)),
+			)
+		}
+
+		in[i] = value
+	}
+
+	return c.results(c.fn.Call(in), token)
+}
+
+// results converts the results of the Go function.
+func (c *GoCallable) results(out []reflect.Value, token ast.Token) (any, error) {
+	if n := len(out); n > 0 && out[n-1].Type() == errorType {
+		if !out[n-1].IsNil() {
+			return nil, hostError(out[n-1].Interface().(error), token)
+		}
+
+		out = out[:n-1]
+	}
+
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	value, err := toValue(out[0])
+	if err != nil {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("%s() returned a value scripts cannot use: %v.", c.name, err))
+	}
+
+	return value, nil
+}
+
+func (c *GoCallable) Arity() int {
+	if c.fn.Type().IsVariadic() {
+		return -1
+	}
+
+	return c.fn.Type().NumIn()
+}
+
+func (c *GoCallable) Name() string {
+	return c.name
+}
+
+func (c *GoCallable) String() string {
+	return "<native fn>"
+}
+
+// hostError turns an error of the host into a runtime error at the call, which scripts
+// can catch. Errors of script functions called back by the host keep their position.
+func hostError(err error, token ast.Token) error {
+	switch err.(type) {
+	case errors.RuntimeError, *errors.ThrownError:
+		return err
+	default:
+		return errors.NewRuntimeError(token, err.Error())
+	}
+}
+
+// ToValue converts a Go value to a Rune value. Numbers become float64, slices and
+// arrays become arrays, maps with string keys and structs become objects, and
+// functions are bound with NewGoCallable. Struct fields are named by their "rune" tag
+// or their name, fields tagged "-" and unexported fields are left out.
+func ToValue(value any) (any, error) {
+	return toValue(reflect.ValueOf(value))
+}
+
+func toValue(v reflect.Value) (any, error) {
+	return convert(v, map[reference]bool{})
+}
+
+// reference is a pointer, map or slice being converted, by its address and type since a
+// struct and its first field share their address.
+type reference struct {
+	address uintptr
+	t       reflect.Type
+}
+
+// convert converts a Go value, visiting holds the references being converted so that a
+// value containing itself fails instead of recursing forever.
+func convert(v reflect.Value, visiting map[reference]bool) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if v.Type().Implements(callableType) && v.CanInterface() {
+		if isNil(v) {
+			return nil, nil
+		}
+
+		return v.Interface(), nil
+	}
+
+	// Arrays and objects of scripts handed back by the host are kept as they are.
+	if v.Type() == objectType || v.Type() == arrayType {
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		return v.Interface(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() || v.Kind() == reflect.Slice && v.Len() == 0 {
+			break
+		}
+
+		ref := reference{v.Pointer(), v.Type()}
+		if visiting[ref] {
+			return nil, fmt.Errorf("cannot convert %s, it contains itself", v.Type())
+		}
+
+		visiting[ref] = true
+		defer delete(visiting, ref)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		return convert(v.Elem(), visiting)
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice, reflect.Array:
+		items := make([]any, v.Len())
+
+		for i := range items {
+			item, err := convert(v.Index(i), visiting)
+			if err != nil {
+				return nil, err
+			}
+
+			items[i] = item
+		}
+
+		return NewArray(items), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		// Go maps have no order, the keys are sorted so that objects print the same on
+		// every run.
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a reflect.Value, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+
+		obj := NewObject()
+
+		for _, key := range keys {
+			value, err := convert(v.MapIndex(key), visiting)
+			if err != nil {
+				return nil, err
+			}
+
+			obj.Set(key.String(), value)
+		}
+
+		return obj, nil
+	case reflect.Struct:
+		obj := NewObject()
+
+		for i := range v.NumField() {
+			name, ok := fieldName(v.Type().Field(i))
+			if !ok {
+				continue
+			}
+
+			value, err := convert(v.Field(i), visiting)
+			if err != nil {
+				return nil, err
+			}
+
+			obj.Set(name, value)
+		}
+
+		return obj, nil
+	case reflect.Func:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		return NewGoCallable("anonymous", v.Interface())
+	}
+
+	return nil, fmt.Errorf("cannot convert %s", v.Type())
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get("rune")
+	if tag == "-" {
+		return "", false
+	}
+
+	if tag != "" {
+		return tag, true
+	}
+
+	return field.Name, true
+}
+
+// fromValue converts a Rune value to the given parameter type, it fails if the value
+// has another type.
+func fromValue(value any, t reflect.Type, call CallFn, token ast.Token) (reflect.Value, bool) {
+	switch t.Kind() {
+	case reflect.Interface:
+		if value == nil {
+			return reflect.Zero(t), true
+		}
+
+		if reflect.TypeOf(value).Implements(t) {
+			return reflect.ValueOf(value).Convert(t), true
+		}
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			return reflect.ValueOf(b).Convert(t), true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f, ok := value.(float64); ok && f == float64(int64(f)) {
+			v := reflect.New(t).Elem()
+			if !v.OverflowInt(int64(f)) {
+				v.SetInt(int64(f))
+				return v, true
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if f, ok := value.(float64); ok && f >= 0 && f == float64(uint64(f)) {
+			v := reflect.New(t).Elem()
+			if !v.OverflowUint(uint64(f)) {
+				v.SetUint(uint64(f))
+				return v, true
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := value.(float64); ok {
+			return reflect.ValueOf(f).Convert(t), true
+		}
+	case reflect.String:
+		if s, ok := value.(string); ok {
+			return reflect.ValueOf(s).Convert(t), true
+		}
+	case reflect.Slice:
+		array, ok := value.(*Array)
+		if !ok {
+			break
+		}
+
+		v := reflect.MakeSlice(t, array.Len(), array.Len())
+
+		for i, item := range array.Items() {
+			elem, ok := fromValue(item, t.Elem(), call, token)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			v.Index(i).Set(elem)
+		}
+
+		return v, true
+	case reflect.Map:
+		obj, ok := value.(*Object)
+		if !ok {
+			break
+		}
+
+		v := reflect.MakeMapWithSize(t, obj.Len())
+
+		for _, key := range obj.Keys() {
+			item, _ := obj.Get(key)
+
+			elem, ok := fromValue(item, t.Elem(), call, token)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			v.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem)
+		}
+
+		return v, true
+	case reflect.Func:
+		if fn, ok := value.(Callable); ok {
+			return callback(fn, t, call, token), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// callback returns a Go function of type t that calls a script function with call. It is
+// only valid during the call it was passed to.
+func callback(fn Callable, t reflect.Type, call CallFn, token ast.Token) reflect.Value {
+	return reflect.MakeFunc(t, func(in []reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, t.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(t.Out(i))
+		}
+
+		// checkParam made sure that the last result is an error.
+		fail := func(err error) []reflect.Value {
+			out[len(out)-1] = reflect.ValueOf(&err).Elem()
+
+			return out
+		}
+
+		args := make([]any, len(in))
+
+		for i, v := range in {
+			arg, err := toValue(v)
+			if err != nil {
+				return fail(errors.NewRuntimeError(token, fmt.Sprintf("Cannot pass %s to %s: %v.", v.Type(), fnName(fn), err)))
+			}
+
+			args[i] = arg
+		}
+
+		if fn.Arity() != -1 && len(args) != fn.Arity() {
+			return fail(errors.NewRuntimeError(
+				token,
+				fmt.Sprintf("Expected %d arguments but got %d.", fn.Arity(), len(args)),
+			))
+		}
+
+		result, err := call(fn, args, token)
+		if err != nil {
+			return fail(err)
+		}
+
+		if len(out) == 2 {
+			value, ok := fromValue(result, t.Out(0), call, token)
+			if !ok {
+				return fail(errors.NewRuntimeError(
+					token,
+					fmt.Sprintf("Expected %s to return %s, got %s.", fnName(fn), describe(t.Out(0)), typeName(result)),
+				))
+			}
+
+			out[0] = value
+		}
+
+		return out
+	})
+}
+
+func fnName(fn Callable) string {
+	if named, ok := fn.(Named); ok {
+		return fmt.Sprintf("'%s'", named.Name())
+	}
+
+	return "function"
+}
+
+// checkFunc reports parameter and result types that cannot be converted.
+func checkFunc(t reflect.Type) error {
+	for i := range t.NumIn() {
+		if err := checkParam(paramAt(t, i)); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case t.NumOut() > 2:
+		return fmt.Errorf("too many results in %s", t)
+	case t.NumOut() == 2 && t.Out(1) != errorType:
+		return fmt.Errorf("the second result of %s must be an error", t)
+	}
+
+	return nil
+}
+
+func checkParam(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Interface,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return nil
+	case reflect.Slice:
+		return checkParam(t.Elem())
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String {
+			return checkParam(t.Elem())
+		}
+	case reflect.Func:
+		// Script functions can fail, a callback returns their error rather than
+		// unwinding the Go code that called it.
+		if t.NumOut() == 0 || t.NumOut() > 2 || t.Out(t.NumOut()-1) != errorType {
+			return fmt.Errorf("unsupported callback type %s, callbacks must return an error or a value and an error", t)
+		}
+
+		if t.NumOut() == 2 {
+			return checkParam(t.Out(0))
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported parameter type %s", t)
+}
+
+// paramAt returns the type of the i-th argument, the extra arguments of a variadic
+// function have the element type of its last parameter.
+func paramAt(t reflect.Type, i int) reflect.Type {
+	if t.IsVariadic() && i >= t.NumIn()-1 {
+		return t.In(t.NumIn() - 1).Elem()
+	}
+
+	return t.In(i)
+}
+
+// describe names the Rune values a Go type accepts, e.g. "an array of numbers".
+func describe(t reflect.Type) string {
+	noun := typeNoun(t, false)
+
+	if strings.IndexAny(noun[:1], "aeiou") == 0 {
+		return "an " + noun
+	}
+
+	return "a " + noun
+}
+
+func typeNoun(t reflect.Type, plural bool) string {
+	var noun string
+
+	switch t.Kind() {
+	case reflect.Bool:
+		noun = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		noun = "integer"
+	case reflect.Float32, reflect.Float64:
+		noun = "number"
+	case reflect.String:
+		noun = "string"
+	case reflect.Slice:
+		noun = "array"
+		if plural {
+			noun = "arrays"
+		}
+
+		if t.Elem().Kind() == reflect.Interface {
+			return noun
+		}
+
+		return noun + " of " + typeNoun(t.Elem(), true)
+	case reflect.Map:
+		noun = "object"
+		if plural {
+			noun = "objects"
+		}
+
+		if t.Elem().Kind() == reflect.Interface {
+			return noun
+		}
+
+		return noun + " of " + typeNoun(t.Elem(), true)
+	case reflect.Func:
+		noun = "function"
+	default:
+		noun = "value"
+	}
+
+	if plural {
+		return noun + "s"
+	}
+
+	return noun
+}
+
+// typeName names the type of a Rune value in error messages.
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case *Array:
+		return "array"
+	case *Object:
+		return "object"
+	case *Instance:
+		return "instance"
+	case Callable:
+		return "function"
+	default:
+		return "value"
+	}
+}
@@ -0,0 +1,298 @@
+package callable_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/errors"
+)
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestToValueCycle(t *testing.T) {
+	n := &node{Name: "a"}
+	n.Next = n
+
+	if _, err := callable.ToValue(n); err == nil || !strings.Contains(err.Error(), "contains itself") {
+		t.Errorf("got error %v, want one about the cycle", err)
+	}
+
+	items := []any{1}
+	items[0] = items
+
+	if _, err := callable.ToValue(items); err == nil {
+		t.Error("converting a slice containing itself succeeded")
+	}
+
+	fn, err := callable.NewGoCallable("loop", func() *node { return n })
+	if err != nil {
+		t.Fatalf("NewGoCallable: %v", err)
+	}
+
+	if _, err := fn.Call(nil, nil, ast.Token{}); err == nil {
+		t.Error("returning a cycle succeeded")
+	}
+}
+
+func TestToValueShared(t *testing.T) {
+	shared := &node{Name: "shared"}
+
+	value, err := callable.ToValue([]*node{shared, shared})
+	if err != nil {
+		t.Fatalf("ToValue: %v", err)
+	}
+
+	if got, want := callable.Stringify(value), `[{Name: "shared", Next: nil}, {Name: "shared", Next: nil}]`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCallbackWithoutError(t *testing.T) {
+	_, err := callable.NewGoCallable("apply", func(f func(string) string, s string) string { return f(s) })
+	if err == nil || !strings.Contains(err.Error(), "callbacks must return an error") {
+		t.Errorf("got error %v, want one about the callback type", err)
+	}
+}
+
+// call binds fn and calls it with args.
+func call(t *testing.T, fn any, args ...any) (any, error) {
+	t.Helper()
+
+	bound, err := callable.NewGoCallable("f", fn)
+	if err != nil {
+		t.Fatalf("NewGoCallable: %v", err)
+	}
+
+	return bound.Call(nil, args, ast.Token{})
+}
+
+// message returns the message of a runtime error, or the error itself.
+func message(err error) string {
+	if runtimeErr, ok := err.(errors.RuntimeError); ok {
+		return runtimeErr.Message()
+	}
+
+	return fmt.Sprint(err)
+}
+
+func array(items ...any) *callable.Array {
+	return callable.NewArray(items)
+}
+
+func object(pairs ...any) *callable.Object {
+	obj := callable.NewObject()
+	for i := 0; i < len(pairs); i += 2 {
+		obj.Set(pairs[i].(string), pairs[i+1])
+	}
+
+	return obj
+}
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+		args []any
+		want any
+	}{
+		{"string and int", func(s string, n int) string { return strings.Repeat(s, n) }, []any{"ab", 2.0}, "abab"},
+		{"uint8", func(n uint8) uint8 { return n + 1 }, []any{254.0}, 255.0},
+		{"float32", func(f float32) float32 { return f / 2 }, []any{3.0}, 1.5},
+		{"bool", func(b bool) bool { return !b }, []any{true}, false},
+		{"slice", func(items []int) int { return len(items) }, []any{array(1.0, 2.0)}, 2.0},
+		{"map", func(m map[string]float64) float64 { return m["a"] + m["b"] }, []any{object("a", 1.0, "b", 2.0)}, 3.0},
+		{"any keeps Rune values", func(v any) bool { _, ok := v.(*callable.Array); return ok }, []any{array(1.0)}, true},
+		{"nil to any", func(v any) bool { return v == nil }, []any{nil}, true},
+		{"variadic", func(sep string, parts ...string) string { return strings.Join(parts, sep) }, []any{"-", "a", "b", "c"}, "a-b-c"},
+		{"variadic without extra arguments", func(sep string, parts ...string) int { return len(parts) }, []any{"-"}, 0.0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := call(t, test.fn, test.args...)
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("got %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArgumentMismatches(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+		args []any
+		want string
+	}{
+		{"string", func(s string) string { return s }, []any{1.0}, "f() expects a string as argument 1, got number."},
+		{"fraction to int", func(s string, n int) int { return n }, []any{"a", 1.5}, "f() expects an integer as argument 2, got number."},
+		{"overflow", func(n uint8) uint8 { return n }, []any{256.0}, "f() expects an integer as argument 1, got number."},
+		{"negative to uint", func(n uint) uint { return n }, []any{-1.0}, "f() expects an integer as argument 1, got number."},
+		{"slice items", func(items []string) int { return len(items) }, []any{array("a", 1.0)}, "f() expects an array of strings as argument 1, got array."},
+		{"map values", func(m map[string]bool) int { return len(m) }, []any{object("a", nil)}, "f() expects an object of booleans as argument 1, got object."},
+		{"object to slice", func(items []any) int { return len(items) }, []any{object()}, "f() expects an array as argument 1, got object."},
+		{"function", func(f func() error) error { return f() }, []any{"f"}, "f() expects a function as argument 1, got string."},
+		{"variadic items", func(parts ...int) int { return len(parts) }, []any{1.0, "2"}, "f() expects an integer as argument 2, got string."},
+		{"too few for variadic", func(sep string, parts ...string) int { return len(parts) }, nil, "Expected at least 1 arguments but got 0."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := call(t, test.fn, test.args...)
+			if got := message(err); got != test.want {
+				t.Errorf("got error %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+type point struct {
+	X      float64 `rune:"x"`
+	Y      int     `rune:"y"`
+	Label  string
+	Hidden string `rune:"-"`
+	secret string
+}
+
+func TestResults(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+		want string
+	}{
+		{"struct", func() point { return point{X: 1.5, Y: 2, Label: "p", Hidden: "h", secret: "s"} }, `{x: 1.5, y: 2, Label: "p"}`},
+		{"pointer to struct", func() *point { return &point{Label: "p"} }, `{x: 0, y: 0, Label: "p"}`},
+		{"map with sorted keys", func() map[string]int { return map[string]int{"b": 2, "a": 1, "c": 3} }, `{a: 1, b: 2, c: 3}`},
+		{"nested", func() map[string][]int { return map[string][]int{"xs": {1, 2}} }, `{xs: [1, 2]}`},
+		{"nil pointer", func() *point { return nil }, "nil"},
+		{"nil slice", func() []int { return nil }, "[]"},
+		{"nil map", func() map[string]int { return nil }, "{}"},
+		{"nil error", func() error { return nil }, "nil"},
+		{"no result", func() {}, "nil"},
+		{"value and nil error", func() (string, error) { return "ok", nil }, "ok"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := call(t, test.fn)
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+
+			if s := callable.Stringify(got); s != test.want {
+				t.Errorf("got %s, want %s", s, test.want)
+			}
+		})
+	}
+}
+
+func TestResultErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+		want string
+	}{
+		{"error", func() error { return fmt.Errorf("failed") }, "failed"},
+		{"value and error", func() (int, error) { return 1, fmt.Errorf("failed") }, "failed"},
+		{"unsupported value", func() chan int { return make(chan int) }, "f() returned a value scripts cannot use: cannot convert chan int."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := call(t, test.fn)
+			if got := message(err); got != test.want {
+				t.Errorf("got error %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+		want string
+	}{
+		{"not a function", 1, "cannot bind f: expected a function, got int"},
+		{"nil function", (func())(nil), "cannot bind f: expected a function, got func()"},
+		{"parameter", func(chan int) {}, "cannot bind f: unsupported parameter type chan int"},
+		{"map key", func(map[int]string) {}, "cannot bind f: unsupported parameter type map[int]string"},
+		{"too many results", func() (int, int, error) { return 0, 0, nil }, "cannot bind f: too many results in func() (int, int, error)"},
+		{"second result", func() (int, int) { return 0, 0 }, "cannot bind f: the second result of func() (int, int) must be an error"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := callable.NewGoCallable("f", test.fn)
+			if got := fmt.Sprint(err); got != test.want {
+				t.Errorf("got error %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArity(t *testing.T) {
+	fixed, _ := callable.NewGoCallable("f", func(a, b string) {})
+	variadic, _ := callable.NewGoCallable("f", func(a string, rest ...string) {})
+
+	if fixed.Arity() != 2 || variadic.Arity() != -1 {
+		t.Errorf("got arities %d and %d, want 2 and -1", fixed.Arity(), variadic.Arity())
+	}
+}
+
+func TestCallbacks(t *testing.T) {
+	double := callable.NewFuncCallable("double", 1, func(args []any) (any, error) {
+		return args[0].(float64) * 2, nil
+	})
+	fail := callable.NewFuncCallable("fail", 0, func(args []any) (any, error) {
+		return nil, fmt.Errorf("callback failed")
+	})
+	text := callable.NewFuncCallable("text", 1, func(args []any) (any, error) {
+		return "text", nil
+	})
+
+	apply := func(f func(float64) (float64, error), x float64) (float64, error) { return f(x) }
+
+	got, err := call(t, apply, double, 2.0)
+	if err != nil || got != 4.0 {
+		t.Errorf("apply(double, 2) = %v, %v, want 4", got, err)
+	}
+
+	_, err = call(t, func(f func() error) error { return f() }, fail)
+	if got := message(err); got != "callback failed" {
+		t.Errorf("got error %q, want the error of the callback", got)
+	}
+
+	_, err = call(t, apply, text, 2.0)
+	if got := message(err); got != "Expected 'text' to return a number, got string." {
+		t.Errorf("got error %q, want a result mismatch", got)
+	}
+
+	_, err = call(t, func(f func(a, b float64) (float64, error)) (float64, error) { return f(1, 2) }, double)
+	if got := message(err); got != "Expected 1 arguments but got 2." {
+		t.Errorf("got error %q, want an arity mismatch", got)
+	}
+
+	// Arguments of the callback are converted like results.
+	each := func(f func(point) error) error { return f(point{X: 1, Label: "p"}) }
+
+	var seen string
+	record := callable.NewFuncCallable("record", 1, func(args []any) (any, error) {
+		seen = callable.Stringify(args[0])
+		return nil, nil
+	})
+
+	if _, err := call(t, each, record); err != nil || seen != `{x: 1, y: 0, Label: "p"}` {
+		t.Errorf("callback got %s, %v", seen, err)
+	}
+}
@@ -0,0 +1,40 @@
+package callable
+
+import (
+	"rune/pkg/ast"
+)
+
+// IdenticalCallable tells whether two values are the same value, where == tells whether
+// they are equal. Arrays and objects are identical when they are the same in memory, so
+// that a change through one is seen through the other.
+type IdenticalCallable struct{}
+
+func NewIdenticalCallable() Callable {
+	return &IdenticalCallable{}
+}
+
+func (c *IdenticalCallable) Call(_ ExecuteBlockFn, args []any, _ ast.Token) (any, error) {
+	return identical(args[0], args[1]), nil
+}
+
+// identical compares arrays and objects by reference and other values with ==. Numbers,
+// strings, booleans and nil cannot change, they are identical when equal.
+func identical(a any, b any) bool {
+	return a == b
+}
+
+func (c *IdenticalCallable) Arity() int {
+	return 2
+}
+
+func (c *IdenticalCallable) Name() string {
+	return "identical"
+}
+
+func (c *IdenticalCallable) Doc() string {
+	return "identical(a, b)\n\nReturns true if a and b are the same value. Unlike ==, arrays and objects are only identical to themselves, not to copies with the same items."
+}
+
+func (c *IdenticalCallable) String() string {
+	return "<native fn>"
+}
@@ -0,0 +1,46 @@
+package callable
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// InsertCallable adds a value to an array in place, before the item at an index.
+type InsertCallable struct{}
+
+func NewInsertCallable() Callable {
+	return &InsertCallable{}
+}
+
+func (c *InsertCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	array, ok := args[0].(*Array)
+	if !ok {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Can only insert into arrays, got %s.", typeName(args[0])))
+	}
+
+	index, err := arrayIndex(array, args[1], true, token)
+	if err != nil {
+		return nil, err
+	}
+
+	array.Insert(index, args[2])
+
+	return nil, nil
+}
+
+func (c *InsertCallable) Arity() int {
+	return 3
+}
+
+func (c *InsertCallable) Name() string {
+	return "insert"
+}
+
+func (c *InsertCallable) Doc() string {
+	return "insert(array, index, value)\n\nAdds the value before the item at the index, the items after it move up by one. An index equal to the length adds it at the end."
+}
+
+func (c *InsertCallable) String() string {
+	return "<native fn>"
+}
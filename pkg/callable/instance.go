@@ -0,0 +1,41 @@
+package callable
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// Instance is an object created by calling a class.
+type Instance struct {
+	class  *ClassCallable
+	fields map[string]any
+}
+
+func NewInstance(class *ClassCallable) *Instance {
+	return &Instance{
+		class:  class,
+		fields: map[string]any{},
+	}
+}
+
+// Get looks up a field first and falls back to a method bound to the instance.
+func (i *Instance) Get(name string, token ast.Token) (any, error) {
+	if value, ok := i.fields[name]; ok {
+		return value, nil
+	}
+
+	if method, ok := i.class.FindMethod(name); ok {
+		return method.Bind(i), nil
+	}
+
+	return nil, errors.NewRuntimeError(token, fmt.Sprintf("Undefined property '%s'.", name))
+}
+
+func (i *Instance) Set(name string, value any) {
+	i.fields[name] = value
+}
+
+func (i *Instance) String() string {
+	return fmt.Sprintf("%s instance", i.class.Name)
+}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	"rune/pkg/ast"
 	"rune/pkg/errors"
+	"rune/pkg/sandbox"
 )
 
 type JsonCallable struct{}
@@ -25,6 +27,19 @@ func (c *JsonCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any,
 	case string:
 		httpClient := http.Client{
 			Timeout: time.Second * 2,
+			// The host was checked against the granted permissions, a redirect must not
+			// lead to another one.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if req.URL.Host != via[0].URL.Host {
+					return fmt.Errorf("redirect to another host %s", req.URL.Host)
+				}
+
+				if len(via) >= 10 {
+					return fmt.Errorf("stopped after 10 redirects")
+				}
+
+				return nil
+			},
 		}
 
 		res, err := httpClient.Get(url)
@@ -39,22 +54,95 @@ func (c *JsonCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any,
 			return nil, errors.NewRuntimeError(token, fmt.Sprintf("Error fetching %s: Status code %d", url, res.StatusCode))
 		}
 
-		jsonRes := map[string]any{}
-
-		if err := json.NewDecoder(res.Body).Decode(&jsonRes); err != nil {
+		jsonRes, err := decodeJSON(json.NewDecoder(res.Body))
+		if err != nil {
 			return nil, errors.NewRuntimeError(token, fmt.Sprintf("Error parsing JSON from %s: %s", url, err.Error()))
 		}
 
+		if _, ok := jsonRes.(*Object); !ok {
+			return nil, errors.NewRuntimeError(token, fmt.Sprintf("Error parsing JSON from %s: expected an object, got %s", url, typeName(jsonRes)))
+		}
+
 		return jsonRes, nil
 	default:
 		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Can only parse strings, got %T", args[0]))
 	}
 }
 
+// decodeJSON reads the next JSON value. Objects keep their keys in the order of the
+// document, numbers become float64.
+func decodeJSON(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := NewObject()
+
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			obj.Set(key.(string), value)
+		}
+
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		items := NewArray(nil)
+
+		for dec.More() {
+			item, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			items.Push(item)
+		}
+
+		_, err := dec.Token()
+		return items, err
+	}
+
+	return token, nil
+}
+
+// Requires returns network access to the host of the URL.
+func (c *JsonCallable) Requires(args []any) []sandbox.Request {
+	raw, ok := args[0].(string)
+	if !ok {
+		return nil
+	}
+
+	host := raw
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	return []sandbox.Request{{Kind: sandbox.Net, Target: host}}
+}
+
 func (c *JsonCallable) Arity() int {
 	return 1
 }
 
+func (c *JsonCallable) Name() string {
+	return "json"
+}
+
+func (c *JsonCallable) Doc() string {
+	return "json(url)\n\nFetches a URL and returns its body parsed as a JSON object. Needs network access to the host."
+}
+
 func (c *JsonCallable) String() string {
 	return "<native json>"
 }
@@ -19,12 +19,12 @@ func (c *LenCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any,
 	}
 
 	switch v := args[0].(type) {
-	case []any:
-		return float64(len(v)), nil
+	case *Array:
+		return float64(v.Len()), nil
 	case string:
 		return float64(len(v)), nil
 	default:
-		return 0, errors.NewRuntimeError(token, fmt.Sprintf("len() can only be called on strings and arrays, got %T", args[0]))
+		return 0, errors.NewRuntimeError(token, fmt.Sprintf("len() can only be called on strings and arrays, got %s", typeName(args[0])))
 	}
 }
 
@@ -32,6 +32,14 @@ func (c *LenCallable) Arity() int {
 	return 1
 }
 
+func (c *LenCallable) Name() string {
+	return "len"
+}
+
+func (c *LenCallable) Doc() string {
+	return "len(value)\n\nReturns the length of an array or a string."
+}
+
 func (c *LenCallable) String() string {
 	return "<native fn>"
 }
@@ -0,0 +1,42 @@
+package callable
+
+// Object is the value of an object literal: string keys mapped to values, kept in the
+// order they were first set so that objects iterate and print the same on every run.
+// Objects are shared by reference.
+type Object struct {
+	keys   []string
+	values map[string]any
+}
+
+func NewObject() *Object {
+	return &Object{values: map[string]any{}}
+}
+
+// Get returns the value of a key and whether the object has it.
+func (o *Object) Get(key string) (any, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+// Set sets the value of a key, a new key goes after the others.
+func (o *Object) Set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+
+	o.values[key] = value
+}
+
+// Keys returns the keys in the order they were first set. The slice must not be
+// modified.
+func (o *Object) Keys() []string {
+	return o.keys
+}
+
+func (o *Object) Len() int {
+	return len(o.keys)
+}
+
+func (o *Object) String() string {
+	return Stringify(o)
+}
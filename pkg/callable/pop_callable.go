@@ -0,0 +1,43 @@
+package callable
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// PopCallable removes the last item of an array in place.
+type PopCallable struct{}
+
+func NewPopCallable() Callable {
+	return &PopCallable{}
+}
+
+func (c *PopCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	array, ok := args[0].(*Array)
+	if !ok {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Can only pop from arrays, got %s.", typeName(args[0])))
+	}
+
+	if array.Len() == 0 {
+		return nil, errors.NewRuntimeError(token, "Can't pop from an empty array.")
+	}
+
+	return array.RemoveAt(array.Len() - 1), nil
+}
+
+func (c *PopCallable) Arity() int {
+	return 1
+}
+
+func (c *PopCallable) Name() string {
+	return "pop"
+}
+
+func (c *PopCallable) Doc() string {
+	return "pop(array)\n\nRemoves the last item of the array and returns it. Fails if the array is empty."
+}
+
+func (c *PopCallable) String() string {
+	return "<native fn>"
+}
@@ -0,0 +1,46 @@
+package callable
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// PushCallable adds values at the end of an array in place, unlike append which returns
+// a new array.
+type PushCallable struct{}
+
+func NewPushCallable() Callable {
+	return &PushCallable{}
+}
+
+func (c *PushCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	if len(args) == 0 {
+		return nil, errors.NewRuntimeError(token, "push() requires an array to push to.")
+	}
+
+	array, ok := args[0].(*Array)
+	if !ok {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Can only push to arrays, got %s.", typeName(args[0])))
+	}
+
+	array.Push(args[1:]...)
+
+	return float64(array.Len()), nil
+}
+
+func (c *PushCallable) Arity() int {
+	return -1
+}
+
+func (c *PushCallable) Name() string {
+	return "push"
+}
+
+func (c *PushCallable) Doc() string {
+	return "push(array, values...)\n\nAdds the values at the end of the array and returns its new length. Every variable holding the array sees them."
+}
+
+func (c *PushCallable) String() string {
+	return "<native fn>"
+}
@@ -0,0 +1,58 @@
+package callable
+
+import (
+	"fmt"
+	"os"
+
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+	"rune/pkg/sandbox"
+)
+
+// ReadFileCallable is a callable that returns the contents of a file as a string.
+// Relative paths are resolved against the working directory.
+type ReadFileCallable struct{}
+
+func NewReadFileCallable() Callable {
+	return &ReadFileCallable{}
+}
+
+func (c *ReadFileCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	path, ok := args[0].(string)
+	if !ok {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("readFile() expects a path string, got %s.", typeName(args[0])))
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Error reading %s: %s", path, err.Error()))
+	}
+
+	return string(contents), nil
+}
+
+// Requires returns read access to the file.
+func (c *ReadFileCallable) Requires(args []any) []sandbox.Request {
+	path, ok := args[0].(string)
+	if !ok {
+		return nil
+	}
+
+	return []sandbox.Request{{Kind: sandbox.Read, Target: path}}
+}
+
+func (c *ReadFileCallable) Arity() int {
+	return 1
+}
+
+func (c *ReadFileCallable) Name() string {
+	return "readFile"
+}
+
+func (c *ReadFileCallable) Doc() string {
+	return "readFile(path)\n\nReturns the contents of a file as a string. Needs read access to the file."
+}
+
+func (c *ReadFileCallable) String() string {
+	return "<native fn>"
+}
@@ -0,0 +1,44 @@
+package callable
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// RemoveAtCallable removes the item at an index of an array in place.
+type RemoveAtCallable struct{}
+
+func NewRemoveAtCallable() Callable {
+	return &RemoveAtCallable{}
+}
+
+func (c *RemoveAtCallable) Call(_ ExecuteBlockFn, args []any, token ast.Token) (any, error) {
+	array, ok := args[0].(*Array)
+	if !ok {
+		return nil, errors.NewRuntimeError(token, fmt.Sprintf("Can only remove from arrays, got %s.", typeName(args[0])))
+	}
+
+	index, err := arrayIndex(array, args[1], false, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return array.RemoveAt(index), nil
+}
+
+func (c *RemoveAtCallable) Arity() int {
+	return 2
+}
+
+func (c *RemoveAtCallable) Name() string {
+	return "removeAt"
+}
+
+func (c *RemoveAtCallable) Doc() string {
+	return "removeAt(array, index)\n\nRemoves the item at the index and returns it, the items after it move down by one."
+}
+
+func (c *RemoveAtCallable) String() string {
+	return "<native fn>"
+}
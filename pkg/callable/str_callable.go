@@ -0,0 +1,33 @@
+package callable
+
+import (
+	"rune/pkg/ast"
+)
+
+// StrCallable converts a value to a string the way print shows it, so that any value
+// can be concatenated to a string.
+type StrCallable struct{}
+
+func NewStrCallable() Callable {
+	return &StrCallable{}
+}
+
+func (c *StrCallable) Call(_ ExecuteBlockFn, args []any, _ ast.Token) (any, error) {
+	return Stringify(args[0]), nil
+}
+
+func (c *StrCallable) Arity() int {
+	return 1
+}
+
+func (c *StrCallable) Name() string {
+	return "str"
+}
+
+func (c *StrCallable) Doc() string {
+	return "str(value)\n\nReturns the value as a string, written the way print shows it."
+}
+
+func (c *StrCallable) String() string {
+	return "<native fn>"
+}
@@ -0,0 +1,110 @@
+package callable
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Stringify renders a value the way print shows it. Integral numbers have no decimals,
+// arrays are written [1, 2], objects {a: 1} with their keys in order, and the strings
+// in them quoted. Functions, classes and instances show their name. An array or object
+// met again inside itself is written [...] or {...}.
+func Stringify(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	return Inspect(value)
+}
+
+// Inspect renders a value like Stringify, except that strings are quoted so that "1"
+// and 1 can be told apart, as assertion messages need.
+func Inspect(value any) string {
+	var s stringer
+	s.write(value)
+
+	return s.sb.String()
+}
+
+// stringer writes values, enclosing holds the arrays and objects being written, by their
+// address, to tell cycles apart from values shared by several items.
+type stringer struct {
+	sb        strings.Builder
+	enclosing []uintptr
+}
+
+func (s *stringer) write(value any) {
+	switch v := value.(type) {
+	case nil:
+		s.sb.WriteString("nil")
+	case string:
+		s.sb.WriteString(strconv.Quote(v))
+	case float64:
+		s.sb.WriteString(formatNumber(v))
+	case *Array:
+		if !s.enter(v) {
+			s.sb.WriteString("[...]")
+			return
+		}
+
+		s.sb.WriteByte('[')
+		for i, item := range v.Items() {
+			if i > 0 {
+				s.sb.WriteString(", ")
+			}
+
+			s.write(item)
+		}
+		s.sb.WriteByte(']')
+
+		s.leave()
+	case *Object:
+		if !s.enter(v) {
+			s.sb.WriteString("{...}")
+			return
+		}
+
+		s.sb.WriteByte('{')
+		for i, key := range v.Keys() {
+			if i > 0 {
+				s.sb.WriteString(", ")
+			}
+
+			value, _ := v.Get(key)
+			s.sb.WriteString(key + ": ")
+			s.write(value)
+		}
+		s.sb.WriteByte('}')
+
+		s.leave()
+	default:
+		s.sb.WriteString(fmt.Sprint(value))
+	}
+}
+
+// enter marks an array or object as being written, it returns false if it already is.
+func (s *stringer) enter(value any) bool {
+	address := reflect.ValueOf(value).Pointer()
+	if slices.Contains(s.enclosing, address) {
+		return false
+	}
+
+	s.enclosing = append(s.enclosing, address)
+
+	return true
+}
+
+func (s *stringer) leave() {
+	s.enclosing = s.enclosing[:len(s.enclosing)-1]
+}
+
+func formatNumber(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%.0f", v)
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
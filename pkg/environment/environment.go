@@ -4,59 +4,98 @@ import (
 	"fmt"
 	"rune/pkg/ast"
 	"rune/pkg/errors"
+	"slices"
 )
 
+// Environment holds the variables of a scope. The globals of a module are looked up
+// by name, since they can be defined at any time. Local scopes keep their variables in
+// slots, in the order the resolver declared them, and are accessed by index.
 type Environment struct {
 	values    map[string]any
+	slots     []any
 	enclosing *Environment
+	globals   *Environment
 }
 
 func NewEnvironment(enclosing *Environment) *Environment {
-	return &Environment{
-		values:    map[string]any{},
+	env := &Environment{
 		enclosing: enclosing,
 	}
+
+	if enclosing != nil {
+		env.globals = enclosing.globals
+	} else {
+		env.values = map[string]any{}
+		env.globals = env
+	}
+
+	return env
+}
+
+// Globals returns the outermost environment of the chain, which holds the globals
+// of the module the environment was created in.
+func (e *Environment) Globals() *Environment {
+	return e.globals
 }
 
 func (e *Environment) String() string {
+	if e.values == nil {
+		return fmt.Sprintf("<env %v>", e.slots)
+	}
+
 	return fmt.Sprintf("<env %v>", e.values)
 }
 
+// Names returns the sorted names of the globals defined in this environment, local
+// scopes have no names.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.values))
+	for name := range e.values {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Define defines a global by name, or puts a local into the next free slot.
 func (e *Environment) Define(name string, value any) {
+	if e.values == nil {
+		e.slots = append(e.slots, value)
+		return
+	}
+
 	e.values[name] = value
 }
 
+// Get returns the value of a global.
 func (e *Environment) Get(token ast.Token) (any, error) {
-	if val, ok := e.values[token.Lexeme]; ok {
+	if val, ok := e.globals.values[token.Lexeme]; ok {
 		return val, nil
 	}
 
-	if e.enclosing != nil {
-		return e.enclosing.Get(token)
-	}
-
 	return nil, errors.NewRuntimeError(token, fmt.Sprintf("Undefined variable '%s'.", token.Lexeme))
 }
 
+// Assign assigns to a global that is already defined.
 func (e *Environment) Assign(token ast.Token, value any) error {
-	if _, ok := e.values[token.Lexeme]; ok {
-		e.values[token.Lexeme] = value
+	if _, ok := e.globals.values[token.Lexeme]; ok {
+		e.globals.values[token.Lexeme] = value
 		return nil
 	}
 
-	if e.enclosing != nil {
-		return e.enclosing.Assign(token, value)
-	}
-
 	return errors.NewRuntimeError(token, fmt.Sprintf("Undefined variable '%s'.", token.Lexeme))
 }
 
-func (e *Environment) AssignAt(distance int, name string, value any) {
-	e.ancestor(distance).values[name] = value
+// AssignAt assigns to the local in the given slot of the environment distance scopes out.
+func (e *Environment) AssignAt(distance int, slot int, value any) {
+	e.ancestor(distance).slots[slot] = value
 }
 
-func (e *Environment) GetAt(distance int, name string) any {
-	return e.ancestor(distance).values[name]
+// GetAt returns the local in the given slot of the environment distance scopes out.
+func (e *Environment) GetAt(distance int, slot int) any {
+	return e.ancestor(distance).slots[slot]
 }
 
 func (e *Environment) ancestor(distance int) *Environment {
@@ -68,3 +107,9 @@ func (e *Environment) ancestor(distance int) *Environment {
 
 	return env
 }
+
+// Lookup returns the value of a global, if it is defined.
+func (e *Environment) Lookup(name string) (any, bool) {
+	val, ok := e.globals.values[name]
+	return val, ok
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"strings"
+	"unicode/utf8"
+)
+
+// A deep trace, such as the one of a stack overflow, only keeps its ends.
+const (
+	traceHead = 10
+	traceTail = 3
+)
+
+// Annotate renders an error followed by the source line it points at, with the
+// offending token underlined and the stack trace, if any. The first line is the plain
+// Error() text:
+//
+//	[line: 3] Operands must be numbers.
+//	 3 |   return n - "a";
+//	   |          ^
+//	  at fib (line 3)
+//	  at <script> (line 12)
+//
+// Errors without a position in a scanned source are returned as they are.
+func Annotate(err error) string {
+	switch e := err.(type) {
+	case RuntimeError:
+		return e.Error() + Snippet(e.token) + traceback(e.trace)
+	case *ThrownError:
+		return e.Error() + Snippet(e.token) + traceback(e.trace)
+	case *ImportError:
+		var sb strings.Builder
+
+		for _, cause := range e.causes {
+			fmt.Fprintf(&sb, "%s\n", Annotate(cause))
+		}
+
+		fmt.Fprintf(&sb, "[line: %d] Cannot import module '%s'.", e.token.Line, e.path)
+		sb.WriteString(Snippet(e.token))
+
+		return sb.String()
+	case *CompileError:
+		lines := make([]string, len(e.errors))
+		for i, cause := range e.errors {
+			lines[i] = Annotate(cause)
+		}
+
+		return strings.Join(lines, "\n")
+	}
+
+	return err.Error()
+}
+
+// Snippet renders the source line of a token with the token underlined, the way
+// Annotate does under the first line of an error. It is empty for tokens without a
+// position in a scanned source.
+func Snippet(token ast.Token) string {
+	number, line, column, ok := token.SourceLine()
+	if !ok {
+		return ""
+	}
+
+	// The underline is clipped to the line, a multi-line string only marks its first line.
+	// It is as wide as the characters of the token, not its bytes.
+	width := min(max(utf8.RuneCountInString(token.Lexeme), 1), max(utf8.RuneCountInString(line[column:]), 1))
+
+	// Tabs are kept in the padding so the underline lines up with the source.
+	var padding strings.Builder
+	for _, char := range line[:column] {
+		if char == '\t' {
+			padding.WriteByte('\t')
+		} else {
+			padding.WriteByte(' ')
+		}
+	}
+
+	gutter := fmt.Sprintf("%d", number)
+
+	return fmt.Sprintf(
+		"\n %s | %s\n %s | %s^%s",
+		gutter,
+		line,
+		strings.Repeat(" ", len(gutter)),
+		padding.String(),
+		strings.Repeat("~", width-1),
+	)
+}
+
+func traceback(trace []Frame) string {
+	var sb strings.Builder
+
+	for i, frame := range trace {
+		if len(trace) > traceHead+traceTail && i >= traceHead && i < len(trace)-traceTail {
+			if i == traceHead {
+				fmt.Fprintf(&sb, "\n  ... %d more frames", len(trace)-traceHead-traceTail)
+			}
+
+			continue
+		}
+
+		fmt.Fprintf(&sb, "\n  %s", frame)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// tokenAt returns a token of the source for the first occurrence of lexeme.
+func tokenAt(t *testing.T, source *ast.Source, lexeme string) ast.Token {
+	t.Helper()
+
+	offset := strings.Index(source.Text, lexeme)
+	if offset < 0 {
+		t.Fatalf("%q is not in the source", lexeme)
+	}
+
+	line := strings.Count(source.Text[:offset], "\n") + 1
+
+	return ast.Token{TokenType: ast.IDENTIFIER, Lexeme: lexeme, Position: ast.Position{Line: line, Offset: offset, Source: source}}
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		lexeme string
+		want   string
+	}{
+		{"single character", "print a;\n", "a", "\n 1 | print a;\n   |       ^"},
+		{"multi-character token", "var x = value + 1;\n", "value", "\n 1 | var x = value + 1;\n   |         ^~~~~"},
+		{"tab-indented line", "{\n\tprint missing;\n}\n", "missing", "\n 2 | \tprint missing;\n   | \t      ^~~~~~~"},
+		{"multibyte characters before the token", "print \"héllo ✓\" + nope;\n", "nope", "\n 1 | print \"héllo ✓\" + nope;\n   |                   ^~~~"},
+		{"multibyte token", "print \"日本語\" - 1;\n", "\"日本語\"", "\n 1 | print \"日本語\" - 1;\n   |       ^~~~~"},
+		{"two-digit line number", strings.Repeat("\n", 11) + "oops;", "oops", "\n 12 | oops;\n    | ^~~~"},
+		{"multi-line string", "print \"one\ntwo\";\n", "\"one\ntwo\"", "\n 1 | print \"one\n   |       ^~~~"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			token := tokenAt(t, ast.NewSource(test.source), test.lexeme)
+
+			if got := errors.Snippet(token); got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSnippetWithoutSource(t *testing.T) {
+	if got := errors.Snippet(ast.Token{Lexeme: "a", Position: ast.Position{Line: 1}}); got != "" {
+		t.Errorf("got %q, want no snippet", got)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	source := ast.NewSource("fun f() {\n  return nil.field;\n}\nf();\n")
+	err := errors.NewRuntimeError(tokenAt(t, source, "field"), "Only objects have properties.")
+	err = err.(errors.RuntimeError).WithTrace([]errors.Frame{{Function: "f", Line: 2}, {Function: errors.ScriptFrame, Line: 4}})
+
+	want := "[line: 2] Only objects have properties.\n 2 |   return nil.field;\n   |              ^~~~~\n  at f (line 2)\n  at <script> (line 4)"
+	if got := errors.Annotate(err); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
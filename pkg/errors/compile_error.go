@@ -0,0 +1,30 @@
+package errors
+
+import "strings"
+
+// CompileError is returned when a program fails to scan, parse or resolve. It holds
+// every error that was found, in the order of the source.
+type CompileError struct {
+	errors []error
+}
+
+func NewCompileError(errors []error) error {
+	return &CompileError{errors: errors}
+}
+
+func (e *CompileError) Error() string {
+	lines := make([]string, len(e.errors))
+	for i, err := range e.errors {
+		lines[i] = err.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e *CompileError) Errors() []error {
+	return e.errors
+}
+
+func (e *CompileError) Unwrap() []error {
+	return e.errors
+}
@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"strings"
+)
+
+// ImportError is returned when an imported module fails to scan, parse or resolve.
+// It reports the module's own errors first, followed by the import that loaded it.
+type ImportError struct {
+	token  ast.Token
+	path   string
+	causes []error
+}
+
+func NewImportError(token ast.Token, path string, causes []error) error {
+	return &ImportError{token: token, path: path, causes: causes}
+}
+
+func (e *ImportError) Error() string {
+	var sb strings.Builder
+
+	for _, cause := range e.causes {
+		fmt.Fprintf(&sb, "%v\n", cause)
+	}
+
+	fmt.Fprintf(&sb, "[line: %d] Cannot import module '%s'.", e.token.Line, e.path)
+
+	return sb.String()
+}
+
+func (e *ImportError) Unwrap() []error {
+	return e.causes
+}
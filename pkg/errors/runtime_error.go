@@ -5,9 +5,30 @@ import (
 	"rune/pkg/ast"
 )
 
+// KindRuntimeError is the kind of errors raised by the interpreter and native functions.
+const KindRuntimeError = "RuntimeError"
+
+// KindPermission is the kind of errors raised when a native function needs a capability
+// that was not granted.
+const KindPermission = "PermissionError"
+
+// KindAssertion is the kind of errors raised by failed assertions.
+const KindAssertion = "AssertionError"
+
+// Kinds of the errors raised when a run exceeds one of its limits. Scripts cannot catch
+// them, so a script cannot keep running past its limits.
+const (
+	KindStepLimit       = "StepLimitError"
+	KindTimeout         = "TimeoutError"
+	KindCancelled       = "CancelledError"
+	KindAllocationLimit = "AllocationLimitError"
+)
+
 type RuntimeError struct {
 	token  ast.Token
 	errMsg string
+	kind   string
+	trace  []Frame
 }
 
 func (e RuntimeError) Error() string {
@@ -18,6 +39,59 @@ func (e RuntimeError) Error() string {
 	)
 }
 
+func (e RuntimeError) Message() string {
+	return e.errMsg
+}
+
+// Token returns the token the error points at.
+func (e RuntimeError) Token() ast.Token {
+	return e.token
+}
+
+func (e RuntimeError) Line() int {
+	return e.token.Line
+}
+
+func (e RuntimeError) Kind() string {
+	return e.kind
+}
+
+// Trace returns the calls that were active when the error occurred, innermost first.
+// It is empty for errors raised by top-level code.
+func (e RuntimeError) Trace() []Frame {
+	return e.trace
+}
+
+func (e RuntimeError) WithTrace(trace []Frame) error {
+	e.trace = trace
+	return e
+}
+
+// Catchable reports whether a catch clause can handle the error.
+func (e RuntimeError) Catchable() bool {
+	switch e.kind {
+	case KindStepLimit, KindTimeout, KindCancelled, KindAllocationLimit:
+		return false
+	default:
+		return true
+	}
+}
+
+// NewLimitError returns an error of one of the limit kinds.
+func NewLimitError(token ast.Token, kind string, msg string) error {
+	return RuntimeError{token: token, errMsg: msg, kind: kind}
+}
+
+// NewPermissionError returns an error of kind KindPermission.
+func NewPermissionError(token ast.Token, msg string) error {
+	return RuntimeError{token: token, errMsg: msg, kind: KindPermission}
+}
+
+// NewAssertionError returns an error of kind KindAssertion.
+func NewAssertionError(token ast.Token, msg string) error {
+	return RuntimeError{token: token, errMsg: msg, kind: KindAssertion}
+}
+
 func NewRuntimeError(token ast.Token, msg string) error {
-	return RuntimeError{token: token, errMsg: msg}
+	return RuntimeError{token: token, errMsg: msg, kind: KindRuntimeError}
 }
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+)
+
+// ThrownError carries a value raised by a throw statement until a catch clause handles it.
+type ThrownError struct {
+	token ast.Token
+	Value any
+	trace []Frame
+}
+
+func NewThrownError(token ast.Token, value any) error {
+	return &ThrownError{token: token, Value: value}
+}
+
+func (e *ThrownError) Error() string {
+	return fmt.Sprintf(
+		"[line: %d] %s",
+		e.token.Line,
+		describeThrown(e.Value),
+	)
+}
+
+// Message describes the thrown value the way an uncaught one is reported.
+func (e *ThrownError) Message() string {
+	return describeThrown(e.Value)
+}
+
+func (e *ThrownError) Line() int {
+	return e.token.Line
+}
+
+// Trace returns the calls that were active when the value was thrown, innermost first.
+func (e *ThrownError) Trace() []Frame {
+	return e.trace
+}
+
+func (e *ThrownError) WithTrace(trace []Frame) error {
+	traced := *e
+	traced.trace = trace
+
+	return &traced
+}
+
+// describeThrown renders an uncaught value, rethrown error objects keep their original message.
+func describeThrown(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return v
+	case float64:
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%.0f", v)
+		}
+	case interface{ Get(string) (any, bool) }:
+		// An object, which this package cannot name.
+		if message, ok := v.Get("message"); ok {
+			if message, ok := message.(string); ok {
+				return message
+			}
+		}
+	}
+
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+// ScriptFrame is the name of the frame running top-level code.
+const ScriptFrame = "<script>"
+
+// Frame is one entry of a stack trace, the function that was running and the line it was at.
+type Frame struct {
+	Function string
+	Line     int
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("at %s (line %d)", f.Function, f.Line)
+}
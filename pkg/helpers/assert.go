@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"reflect"
 	"rune/pkg/callable"
 )
 
@@ -16,25 +17,101 @@ func IsTruthy(val any) bool {
 		return len(i2) != 0
 	case float64:
 		return i2 != 0.0
-	case *callable.FunctionCallable:
+	case *callable.FunctionCallable, *callable.ClassCallable, *callable.Instance:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsEqual compares values the way == does in scripts. Arrays and objects are equal when
+// their items are, however deeply nested, while functions, classes and instances are
+// only equal to themselves. Arrays and objects that contain themselves are compared
+// without looping forever: a pair met again while it is being compared is taken as
+// equal, so two cycles are equal when they have the same shape and items.
 func IsEqual(left any, right any) bool {
-	if left == nil && right == nil {
+	return isEqual(left, right, nil)
+}
+
+// visit is a pair of arrays or objects being compared, by their address.
+type visit struct {
+	left  uintptr
+	right uintptr
+}
+
+// isEqual compares values, visiting holds the pairs of arrays and objects being
+// compared. It is only made once a pair is met.
+func isEqual(left any, right any, visiting map[visit]bool) bool {
+	switch l := left.(type) {
+	case *callable.Array:
+		r, ok := right.(*callable.Array)
+		if !ok || l.Len() != r.Len() {
+			return false
+		}
+
+		if l == r {
+			return true
+		}
+
+		if visiting, ok = enter(visiting, l, r); !ok {
+			return true
+		}
+
+		for i, item := range l.Items() {
+			if !isEqual(item, r.Get(i), visiting) {
+				return false
+			}
+		}
+
 		return true
-	}
+	case *callable.Object:
+		r, ok := right.(*callable.Object)
+		if !ok || l.Len() != r.Len() {
+			return false
+		}
 
-	if left == nil {
-		return false
+		if l == r {
+			return true
+		}
+
+		if visiting, ok = enter(visiting, l, r); !ok {
+			return true
+		}
+
+		// The order of the keys does not matter.
+		for _, key := range l.Keys() {
+			value, _ := l.Get(key)
+
+			other, ok := r.Get(key)
+			if !ok || !isEqual(value, other, visiting) {
+				return false
+			}
+		}
+
+		return true
 	}
 
 	return left == right
 }
 
+// enter marks a pair of arrays or objects as being compared. It returns false if the
+// pair already is.
+func enter(visiting map[visit]bool, left any, right any) (map[visit]bool, bool) {
+	pair := visit{reflect.ValueOf(left).Pointer(), reflect.ValueOf(right).Pointer()}
+
+	if visiting[pair] {
+		return visiting, false
+	}
+
+	if visiting == nil {
+		visiting = make(map[visit]bool)
+	}
+
+	visiting[pair] = true
+
+	return visiting, true
+}
+
 func IsString(val any) bool {
 	_, ok := val.(string)
 	return ok
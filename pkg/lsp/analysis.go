@@ -0,0 +1,187 @@
+package lsp
+
+import (
+	"sort"
+
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+	"rune/pkg/rune"
+)
+
+// analysis is what the server knows about the text of a document: its tokens, syntax
+// tree, the symbols the resolver recorded and the errors found on the way.
+type analysis struct {
+	tokens  []ast.Token
+	stmts   []ast.Stmt
+	symbols *rune.Symbols
+	// errors are the scan and parse errors, or the resolve errors if there are none,
+	// like a run of the program reports them.
+	errors []error
+}
+
+// analyze scans, parses and resolves a program without running it. Each stage keeps
+// going after errors so that the rest of the document can still be navigated.
+func analyze(text string) *analysis {
+	tokens, scanErrors := rune.Scan([]byte(text))
+	stmts, parseErrors := rune.ParseStmts(tokens)
+
+	resolver := rune.NewResolver(nil)
+	symbols := resolver.RecordSymbols()
+	resolveErrors := resolver.ResolveStmts(stmts)
+
+	a := &analysis{tokens: tokens, stmts: stmts, symbols: symbols}
+
+	a.errors = append(scanErrors, parseErrors...)
+	if len(a.errors) == 0 {
+		a.errors = resolveErrors
+	}
+
+	return a
+}
+
+// diagnostics converts the errors of the analysis for the client.
+func (a *analysis) diagnostics(d *document) []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	for _, err := range a.errors {
+		e, ok := err.(errors.RuntimeError)
+		if !ok {
+			continue
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    d.tokenRange(e.Token()),
+			Severity: SeverityError,
+			Source:   "rune",
+			Message:  e.Message(),
+		})
+	}
+
+	return diagnostics
+}
+
+// tokenAt returns the index of the token containing a byte offset, or touching it from
+// the left so that a cursor right after an identifier still finds it. It returns -1
+// when there is none.
+func (a *analysis) tokenAt(offset int) int {
+	i := sort.Search(len(a.tokens), func(i int) bool {
+		return a.tokens[i].Offset+len(a.tokens[i].Lexeme) >= offset
+	})
+
+	if i < len(a.tokens) && a.tokens[i].TokenType != ast.EOF && a.tokens[i].Offset <= offset {
+		return i
+	}
+
+	return -1
+}
+
+// identifierAt returns the identifier at a byte offset, if any.
+func (a *analysis) identifierAt(offset int) (ast.Token, bool) {
+	i := a.tokenAt(offset)
+	if i < 0 || a.tokens[i].TokenType != ast.IDENTIFIER {
+		return ast.Token{}, false
+	}
+
+	return a.tokens[i], true
+}
+
+// symbolAt returns the symbol declared or referred to by the identifier at an offset.
+func (a *analysis) symbolAt(offset int) (*rune.Symbol, ast.Token) {
+	token, ok := a.identifierAt(offset)
+	if !ok {
+		return nil, ast.Token{}
+	}
+
+	return a.symbols.At(token.Offset), token
+}
+
+// visible returns the symbols that can be referred to at a byte offset: every global and
+// the locals declared before the offset in a scope that contains it.
+func (a *analysis) visible(offset int) []*rune.Symbol {
+	var symbols []*rune.Symbol
+
+	for _, symbol := range a.symbols.Declared {
+		if symbol.Global {
+			symbols = append(symbols, symbol)
+			continue
+		}
+
+		if symbol.Name.Offset < offset && offset <= a.scopeEnd(symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	return symbols
+}
+
+// scopeEnd returns the offset of the brace closing the scope of a local. The syntax tree
+// does not keep braces, so the scope is found in the tokens: parameters and catch
+// variables live in the block following their parentheses, other locals in the block
+// they are declared in.
+func (a *analysis) scopeEnd(symbol *rune.Symbol) int {
+	i := sort.Search(len(a.tokens), func(i int) bool {
+		return a.tokens[i].Offset >= symbol.Name.Offset
+	})
+
+	if symbol.Kind == rune.SymbolParameter || symbol.Kind == rune.SymbolCatchVariable {
+		if end, ok := a.matching(i, ast.LEFT_PAREN, ast.RIGHT_PAREN); ok {
+			next := end + 1
+			if next < len(a.tokens) && a.tokens[next].TokenType == ast.ARROW {
+				next++
+			}
+
+			if next < len(a.tokens) && a.tokens[next].TokenType == ast.LEFT_BRACE {
+				if end, ok := a.matching(next+1, ast.LEFT_BRACE, ast.RIGHT_BRACE); ok {
+					return a.tokens[end].Offset
+				}
+			}
+		}
+	}
+
+	if end, ok := a.matching(i, ast.LEFT_BRACE, ast.RIGHT_BRACE); ok {
+		return a.tokens[end].Offset
+	}
+
+	return a.tokens[len(a.tokens)-1].Offset
+}
+
+// matching returns the index of the first close token from i on that is not matched by
+// an open token after i.
+func (a *analysis) matching(i int, open ast.TokenType, close ast.TokenType) (int, bool) {
+	depth := 0
+
+	for ; i < len(a.tokens); i++ {
+		switch a.tokens[i].TokenType {
+		case open:
+			depth++
+		case close:
+			if depth == 0 {
+				return i, true
+			}
+
+			depth--
+		}
+	}
+
+	return 0, false
+}
+
+// blockEnd returns the offset right after the block opened by the first '{' from the
+// token at an offset, used for the ranges of document symbols.
+func (a *analysis) blockEnd(offset int) int {
+	i := sort.Search(len(a.tokens), func(i int) bool {
+		return a.tokens[i].Offset >= offset
+	})
+
+	for ; i < len(a.tokens); i++ {
+		if a.tokens[i].TokenType == ast.LEFT_BRACE {
+			if end, ok := a.matching(i+1, ast.LEFT_BRACE, ast.RIGHT_BRACE); ok {
+				return a.tokens[end].Offset + 1
+			}
+
+			break
+		}
+	}
+
+	return a.tokens[len(a.tokens)-1].Offset
+}
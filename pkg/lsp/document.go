@@ -0,0 +1,97 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"rune/pkg/ast"
+)
+
+// document is an open text document and the analysis of its current text.
+type document struct {
+	uri  string
+	text string
+	// lineStarts holds the byte offset of the start of every line.
+	lineStarts []int
+	analysis   *analysis
+}
+
+func newDocument(uri string, text string) *document {
+	d := &document{uri: uri, text: text, lineStarts: []int{0}}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			d.lineStarts = append(d.lineStarts, i+1)
+		}
+	}
+
+	d.analysis = analyze(text)
+
+	return d
+}
+
+// offset converts a protocol position to a byte offset, clamped to the text.
+func (d *document) offset(pos Position) int {
+	if pos.Line < 0 {
+		return 0
+	}
+
+	if pos.Line >= len(d.lineStarts) {
+		return len(d.text)
+	}
+
+	start := d.lineStarts[pos.Line]
+	end := len(d.text)
+	if pos.Line+1 < len(d.lineStarts) {
+		end = d.lineStarts[pos.Line+1]
+	}
+
+	offset, units := start, 0
+	for offset < end && units < pos.Character {
+		r, size := utf8.DecodeRuneInString(d.text[offset:end])
+		if r == '\n' {
+			break
+		}
+
+		units += utf16.RuneLen(r)
+		offset += size
+	}
+
+	return offset
+}
+
+// position converts a byte offset to a protocol position.
+func (d *document) position(offset int) Position {
+	offset = min(max(offset, 0), len(d.text))
+
+	line := 0
+	for line+1 < len(d.lineStarts) && d.lineStarts[line+1] <= offset {
+		line++
+	}
+
+	units := 0
+	for _, r := range d.text[d.lineStarts[line]:offset] {
+		units += utf16.RuneLen(r)
+	}
+
+	return Position{Line: line, Character: units}
+}
+
+// tokenRange returns the range covered by a token, multi-line strings included.
+func (d *document) tokenRange(token ast.Token) Range {
+	end := token.Offset + len(token.Lexeme)
+
+	return Range{Start: d.position(token.Offset), End: d.position(end)}
+}
+
+// lineText returns the text of a line without its line break.
+func (d *document) lineText(line int) string {
+	start := d.lineStarts[line]
+	end := len(d.text)
+	if line+1 < len(d.lineStarts) {
+		end = d.lineStarts[line+1]
+	}
+
+	return strings.TrimRight(d.text[start:end], "\r\n")
+}
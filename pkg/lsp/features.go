@@ -0,0 +1,313 @@
+package lsp
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/rune"
+)
+
+// definition returns where the identifier at a position was declared, or nil for natives
+// and names the program never declares.
+func (s *Server) definition(params TextDocumentPositionParams) *Location {
+	d, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+
+	symbol, _ := d.analysis.symbolAt(d.offset(params.Position))
+	if symbol == nil {
+		return nil
+	}
+
+	return &Location{URI: d.uri, Range: d.tokenRange(symbol.Name)}
+}
+
+// references returns every identifier referring to the same declaration as the one at a
+// position, in the order they appear.
+func (s *Server) references(params ReferenceParams) []Location {
+	locations := []Location{}
+
+	d, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return locations
+	}
+
+	symbol, _ := d.analysis.symbolAt(d.offset(params.Position))
+	if symbol == nil {
+		return locations
+	}
+
+	tokens := slices.Clone(symbol.References)
+	if params.Context.IncludeDeclaration {
+		tokens = append(tokens, symbol.Name)
+	}
+
+	slices.SortFunc(tokens, func(a, b ast.Token) int {
+		return a.Offset - b.Offset
+	})
+
+	for _, token := range tokens {
+		locations = append(locations, Location{URI: d.uri, Range: d.tokenRange(token)})
+	}
+
+	return locations
+}
+
+// hover describes the identifier at a position: the signature and arity of functions,
+// classes and natives, and what declared other names.
+func (s *Server) hover(params TextDocumentPositionParams) *Hover {
+	d, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+
+	symbol, token := d.analysis.symbolAt(d.offset(params.Position))
+
+	var contents string
+	switch {
+	case symbol != nil:
+		contents = describeSymbol(symbol)
+	case token.Lexeme != "":
+		native, ok := rune.Builtins()[token.Lexeme]
+		if !ok {
+			return nil
+		}
+
+		contents = describeNative(token.Lexeme, native)
+	default:
+		return nil
+	}
+
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: contents},
+		Range:    d.tokenRange(token),
+	}
+}
+
+func describeSymbol(symbol *rune.Symbol) string {
+	name := symbol.Name.Lexeme
+
+	switch symbol.Kind {
+	case rune.SymbolFunction:
+		return codeBlock(signature("fun "+name, symbol.Function)) + describeArity(len(symbol.Function.Parameters))
+	case rune.SymbolClass:
+		arity := 0
+		if symbol.Function != nil {
+			arity = len(symbol.Function.Parameters)
+		}
+
+		return codeBlock(signature("class "+name, symbol.Function)) + describeArity(arity)
+	case rune.SymbolParameter:
+		return codeBlock(name) + "Parameter"
+	case rune.SymbolCatchVariable:
+		return codeBlock(name) + "Catch variable"
+	case rune.SymbolImport:
+		return codeBlock(name) + "Imported"
+	}
+
+	if symbol.Global {
+		return codeBlock("var "+name) + "Global variable"
+	}
+
+	return codeBlock("var "+name) + "Local variable"
+}
+
+func describeNative(name string, native callable.Callable) string {
+	doc := name + "()"
+	if documented, ok := native.(callable.Documented); ok {
+		doc = documented.Doc()
+	}
+
+	header, rest, _ := strings.Cut(doc, "\n")
+	contents := codeBlock(header)
+
+	if rest = strings.TrimSpace(rest); rest != "" {
+		contents += rest + "\n\n"
+	}
+
+	return contents + describeArity(native.Arity())
+}
+
+// signature renders a declaration with its parameters, classes without an initializer
+// have none to show.
+func signature(declaration string, fn *ast.FunctionStmt) string {
+	if fn == nil {
+		return declaration
+	}
+
+	params := make([]string, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		params[i] = param.Lexeme
+	}
+
+	return declaration + "(" + strings.Join(params, ", ") + ")"
+}
+
+func describeArity(arity int) string {
+	if arity < 0 {
+		return "Arity: any"
+	}
+
+	return fmt.Sprintf("Arity: %d", arity)
+}
+
+func codeBlock(code string) string {
+	return "```rune\n" + code + "\n```\n"
+}
+
+// completion offers the keywords, natives and the names visible at a position that start
+// with the identifier being typed there.
+func (s *Server) completion(params TextDocumentPositionParams) []CompletionItem {
+	items := []CompletionItem{}
+
+	d, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return items
+	}
+
+	offset := d.offset(params.Position)
+
+	prefix := ""
+	if token, ok := d.analysis.identifierAt(offset); ok {
+		prefix = d.text[token.Offset:offset]
+	}
+
+	seen := make(map[string]bool)
+	add := func(item CompletionItem) {
+		if item.Label == "" || seen[item.Label] || !strings.HasPrefix(item.Label, prefix) {
+			return
+		}
+
+		seen[item.Label] = true
+		items = append(items, item)
+	}
+
+	// Later declarations shadow earlier ones, inner scopes are declared last.
+	visible := d.analysis.visible(offset)
+	for i := len(visible) - 1; i >= 0; i-- {
+		add(completionItem(visible[i]))
+	}
+
+	for name, native := range rune.Builtins() {
+		detail := name + "()"
+		if documented, ok := native.(callable.Documented); ok {
+			detail, _, _ = strings.Cut(documented.Doc(), "\n")
+		}
+
+		add(CompletionItem{Label: name, Kind: CompletionFunction, Detail: detail})
+	}
+
+	for keyword := range ast.Keywords {
+		add(CompletionItem{Label: keyword, Kind: CompletionKeyword})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+
+	return items
+}
+
+func completionItem(symbol *rune.Symbol) CompletionItem {
+	item := CompletionItem{Label: symbol.Name.Lexeme, Kind: CompletionVariable}
+
+	switch symbol.Kind {
+	case rune.SymbolFunction:
+		item.Kind = CompletionFunction
+		item.Detail = signature("fun "+symbol.Name.Lexeme, symbol.Function)
+	case rune.SymbolClass:
+		item.Kind = CompletionClass
+		item.Detail = signature("class "+symbol.Name.Lexeme, symbol.Function)
+	}
+
+	return item
+}
+
+// documentSymbols returns the named functions and classes of a document, with the
+// functions declared in their bodies and the methods of classes as children.
+func (s *Server) documentSymbols(params DocumentSymbolParams) []DocumentSymbol {
+	d, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return []DocumentSymbol{}
+	}
+
+	return d.symbolsIn(d.analysis.stmts)
+}
+
+func (d *document) symbolsIn(stmts []ast.Stmt) []DocumentSymbol {
+	symbols := []DocumentSymbol{}
+
+	for _, stmt := range stmts {
+		symbols = append(symbols, d.symbolsOf(stmt)...)
+	}
+
+	return symbols
+}
+
+func (d *document) symbolsOf(stmt ast.Stmt) []DocumentSymbol {
+	switch stmt := stmt.(type) {
+	case *ast.FunctionStmt:
+		return []DocumentSymbol{d.functionSymbol(stmt, SymbolFunction)}
+	case *ast.ClassStmt:
+		class := DocumentSymbol{
+			Name:           stmt.Name.Lexeme,
+			Kind:           SymbolClass,
+			Range:          Range{Start: d.position(stmt.Name.Offset), End: d.position(d.analysis.blockEnd(stmt.Name.Offset))},
+			SelectionRange: d.tokenRange(stmt.Name),
+		}
+
+		for _, method := range stmt.Methods {
+			class.Children = append(class.Children, d.functionSymbol(method, SymbolMethod))
+		}
+
+		return []DocumentSymbol{class}
+	case *ast.ExportStmt:
+		return d.symbolsOf(stmt.Declaration)
+	case *ast.TestStmt:
+		return d.symbolsIn(stmt.Function.Body)
+	case *ast.BlockStmt:
+		return d.symbolsIn(stmt.Stmts)
+	case *ast.IfStmt:
+		symbols := d.symbolsOf(stmt.Then)
+		if stmt.El != nil {
+			symbols = append(symbols, d.symbolsOf(stmt.El)...)
+		}
+
+		return symbols
+	case *ast.WhileStmt:
+		return d.symbolsOf(stmt.Body)
+	case *ast.TryStmt:
+		symbols := d.symbolsIn(stmt.Body)
+		symbols = append(symbols, d.symbolsIn(stmt.CatchBody)...)
+
+		return append(symbols, d.symbolsIn(stmt.FinallyBody)...)
+	}
+
+	return nil
+}
+
+func (d *document) functionSymbol(fn *ast.FunctionStmt, kind int) DocumentSymbol {
+	prefix := "fun "
+	if kind == SymbolMethod {
+		prefix = ""
+	}
+
+	symbol := DocumentSymbol{
+		Name:           fn.Name.Lexeme,
+		Detail:         signature(prefix+fn.Name.Lexeme, fn),
+		Kind:           kind,
+		Range:          Range{Start: d.position(fn.Name.Offset), End: d.position(d.analysis.blockEnd(fn.Name.Offset))},
+		SelectionRange: d.tokenRange(fn.Name),
+	}
+
+	if children := d.symbolsIn(fn.Body); len(children) > 0 {
+		symbol.Children = children
+	}
+
+	return symbol
+}
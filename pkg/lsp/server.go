@@ -0,0 +1,196 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Server is a language server for rune scripts. It speaks the Language Server Protocol
+// over a pair of streams and handles one message at a time, in the order they arrive.
+type Server struct {
+	out         io.Writer
+	documents   map[string]*document
+	initialized bool
+	shutdown    bool
+	version     string
+}
+
+func NewServer(version string) *Server {
+	return &Server{
+		documents: make(map[string]*document),
+		version:   version,
+	}
+}
+
+// Serve reads messages from in and writes the responses and notifications to out until
+// the client sends exit or closes in. It returns the exit code the process should exit
+// with: 0 if the client asked for a shutdown first, 1 otherwise.
+func (s *Server) Serve(in io.Reader, out io.Writer) int {
+	s.out = out
+	reader := bufio.NewReader(in)
+
+	for {
+		body, err := ReadMessage(reader)
+		if err != nil {
+			break
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			null := json.RawMessage("null")
+			s.send(&message{ID: &null, Error: &responseError{Code: codeParseError, Message: err.Error()}})
+			continue
+		}
+
+		if msg.Method == "exit" {
+			break
+		}
+
+		s.handle(&msg)
+	}
+
+	if s.shutdown {
+		return 0
+	}
+
+	return 1
+}
+
+// handle answers a request or processes a notification. Notifications have no ID and get
+// no response, even when they fail.
+func (s *Server) handle(msg *message) {
+	result, err := s.dispatch(msg)
+
+	if msg.ID == nil {
+		return
+	}
+
+	response := &message{ID: msg.ID}
+	if err != nil {
+		response.Error = err
+	} else {
+		raw, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			response.Error = &responseError{Code: codeInvalidRequest, Message: marshalErr.Error()}
+		} else {
+			response.Result = (*json.RawMessage)(&raw)
+		}
+	}
+
+	s.send(response)
+}
+
+func (s *Server) dispatch(msg *message) (any, *responseError) {
+	if msg.Method == "initialize" {
+		s.initialized = true
+		return s.initialize(), nil
+	}
+
+	if !s.initialized {
+		return nil, &responseError{Code: codeServerNotInitialized, Message: "The server is not initialized."}
+	}
+
+	switch msg.Method {
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		return decode(msg.Params, &params, func() any {
+			s.update(params.TextDocument.URI, params.TextDocument.Text)
+			return nil
+		})
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		return decode(msg.Params, &params, func() any {
+			if n := len(params.ContentChanges); n > 0 {
+				s.update(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+			}
+			return nil
+		})
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		return decode(msg.Params, &params, func() any {
+			delete(s.documents, params.TextDocument.URI)
+			s.publish(params.TextDocument.URI, []Diagnostic{})
+			return nil
+		})
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		return decode(msg.Params, &params, func() any {
+			return s.definition(params)
+		})
+	case "textDocument/references":
+		var params ReferenceParams
+		return decode(msg.Params, &params, func() any {
+			return s.references(params)
+		})
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		return decode(msg.Params, &params, func() any {
+			return s.hover(params)
+		})
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		return decode(msg.Params, &params, func() any {
+			return s.completion(params)
+		})
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		return decode(msg.Params, &params, func() any {
+			return s.documentSymbols(params)
+		})
+	}
+
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("Method '%s' not found.", msg.Method)}
+}
+
+// decode unmarshals the params of a message and runs the handler with them.
+func decode[T any](raw json.RawMessage, params *T, handler func() any) (any, *responseError) {
+	if err := json.Unmarshal(raw, params); err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	return handler(), nil
+}
+
+func (s *Server) initialize() InitializeResult {
+	result := InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync:       textDocumentSyncFull,
+			DefinitionProvider:     true,
+			ReferencesProvider:     true,
+			HoverProvider:          true,
+			DocumentSymbolProvider: true,
+		},
+	}
+
+	result.ServerInfo.Name = "rune"
+	result.ServerInfo.Version = s.version
+
+	return result
+}
+
+// update analyzes the new text of a document and publishes its diagnostics.
+func (s *Server) update(uri string, text string) {
+	d := newDocument(uri, text)
+	s.documents[uri] = d
+	s.publish(uri, d.analysis.diagnostics(d))
+}
+
+func (s *Server) publish(uri string, diagnostics []Diagnostic) {
+	params, _ := json.Marshal(PublishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+
+	s.send(&message{Method: "textDocument/publishDiagnostics", Params: params})
+}
+
+func (s *Server) send(msg *message) {
+	msg.JSONRPC = "2.0"
+
+	// A client that stopped reading cannot be told about it, the next read ends the loop.
+	_ = WriteMessage(s.out, msg)
+}
@@ -0,0 +1,27 @@
+package rune
+
+import (
+	"rune/pkg/callable"
+	"rune/pkg/helpers"
+)
+
+// Builtins returns the native functions every script can call, by name.
+func Builtins() map[string]callable.Callable {
+	return map[string]callable.Callable{
+		"clock":        callable.NewClockCallable(),
+		"len":          callable.NewLenCallable(),
+		"append":       callable.NewAppendCallable(),
+		"push":         callable.NewPushCallable(),
+		"pop":          callable.NewPopCallable(),
+		"insert":       callable.NewInsertCallable(),
+		"removeAt":     callable.NewRemoveAtCallable(),
+		"json":         callable.NewJsonCallable(),
+		"readFile":     callable.NewReadFileCallable(),
+		"env":          callable.NewEnvCallable(),
+		"assert":       callable.NewAssertCallable(),
+		"assertEqual":  callable.NewAssertEqualCallable(helpers.IsEqual),
+		"assertThrows": callable.NewAssertThrowsCallable(),
+		"identical":    callable.NewIdenticalCallable(),
+		"str":          callable.NewStrCallable(),
+	}
+}
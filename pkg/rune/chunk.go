@@ -0,0 +1,105 @@
+package rune
+
+import (
+	"rune/pkg/ast"
+	"sort"
+)
+
+type opcode byte
+
+// Operands follow their opcode in the code, "u16" operands are big-endian and index the
+// constant pool unless noted otherwise.
+const (
+	opConstant     opcode = iota // u16 constant
+	opNil                        //
+	opTrue                       //
+	opFalse                      //
+	opPop                        //
+	opPopLocals                  // u16 count, closes upvalues of the popped slots
+	opPopBelow                   // u16 count, pops the values below the top one
+	opGetLocal                   // u16 slot
+	opSetLocal                   // u16 slot
+	opGetUpvalue                 // u16 upvalue
+	opSetUpvalue                 // u16 upvalue
+	opDefineGlobal               // u16 name
+	opGetGlobal                  // u16 name
+	opSetGlobal                  // u16 name
+	opGetProperty                // u16 name
+	opSetProperty                // u16 name
+	opGetIndex                   //
+	opSetIndex                   //
+	opEqual                      //
+	opNotEqual                   //
+	opGreater                    //
+	opGreaterEqual               //
+	opLess                       //
+	opLessEqual                  //
+	opAdd                        //
+	opSubtract                   //
+	opMultiply                   //
+	opDivide                     //
+	opNot                        //
+	opNegate                     //
+	opPrint                      //
+	opJump                       // u16 forward offset
+	opJumpIfFalse                // u16 forward offset, keeps the condition
+	opLoop                       // u16 backward offset
+	opCall                       // u8 argument count
+	opClosure                    // u16 function, then (u8 isLocal, u16 index) per upvalue
+	opReturn                     //
+	opClass                      // u16 name
+	opMethod                     // u16 name
+	opArray                      // u16 item count
+	opObject                     // u16 pair count, keys are pushed before their values
+	opTry                        // u16 forward offset of the handler
+	opEndTry                     //
+	opCatch                      // u16 forward offset taken when the error is not catchable
+	opRethrow                    //
+	opThrow                      //
+	opImport                     // u16 import statement
+	opExport                     // u16 name
+	opTest                       // u16 test statement, pops the function of the test
+)
+
+// chunk is the compiled code of one function.
+type chunk struct {
+	code      []byte
+	constants []any
+	// tokens maps code offsets to the tokens they were compiled from, for error messages
+	// and stack traces. A span starts a run of code compiled from the same token.
+	tokens []ast.Token
+	spans  []tokenSpan
+}
+
+type tokenSpan struct {
+	offset int
+	token  int
+}
+
+func (c *chunk) write(token ast.Token, bytes ...byte) {
+	if len(c.spans) == 0 || c.tokens[c.spans[len(c.spans)-1].token] != token {
+		c.tokens = append(c.tokens, token)
+		c.spans = append(c.spans, tokenSpan{offset: len(c.code), token: len(c.tokens) - 1})
+	}
+
+	c.code = append(c.code, bytes...)
+}
+
+func (c *chunk) addConstant(value any) int {
+	c.constants = append(c.constants, value)
+	return len(c.constants) - 1
+}
+
+// tokenAt returns the token the code at offset was compiled from.
+func (c *chunk) tokenAt(offset int) ast.Token {
+	i := sort.Search(len(c.spans), func(i int) bool { return c.spans[i].offset > offset })
+	if i == 0 {
+		return ast.Token{}
+	}
+
+	return c.tokens[c.spans[i-1].token]
+}
+
+func (c *chunk) readUint16(offset int) int {
+	return int(c.code[offset])<<8 | int(c.code[offset+1])
+}
@@ -0,0 +1,1006 @@
+package rune
+
+import (
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/environment"
+	"rune/pkg/errors"
+	"slices"
+)
+
+const maxOperand = 1<<16 - 1
+
+type local struct {
+	name  string
+	depth int
+}
+
+type upvalueRef struct {
+	index   int
+	isLocal bool
+}
+
+// loopState tracks the jumps out of a loop that are patched once its end is known.
+type loopState struct {
+	label      string
+	localCount int
+	tryCount   int
+	breaks     []int
+	continues  []int
+}
+
+// tryState is a region protected by a try statement. A jump leaving the region pops its
+// handler, if one is active, and runs its finally block on the way out.
+type tryState struct {
+	localCount int
+	scopeDepth int
+	loopCount  int
+	handler    bool
+	finally    []ast.Stmt
+}
+
+// funcState is the compiler state of the function being compiled.
+type funcState struct {
+	enclosing  *funcState
+	function   *compiledFunction
+	kind       functionType
+	locals     []local
+	upvalues   []upvalueRef
+	scopeDepth int
+	loops      []*loopState
+	tries      []*tryState
+	names      map[string]int
+}
+
+// compiler translates resolved statements into bytecode for the VM. Variables are
+// resolved again here, locals live in stack slots and captured ones become upvalues.
+type compiler struct {
+	state   *funcState
+	globals *environment.Environment
+	err     error
+}
+
+// compile compiles the statements of a script or module whose globals are given.
+func compile(stmts []ast.Stmt, globals *environment.Environment) (*compiledFunction, error) {
+	c := &compiler{globals: globals}
+	c.beginFunction(functionTypeNone, errors.ScriptFrame)
+	c.state.function.isScript = true
+
+	for _, stmt := range stmts {
+		if err := stmt.Accept(c); err != nil {
+			return nil, err
+		}
+	}
+
+	c.emit(ast.Token{}, opNil)
+	c.emit(ast.Token{}, opReturn)
+
+	fn, _ := c.endFunction()
+
+	return fn, c.err
+}
+
+func (c *compiler) beginFunction(kind functionType, name string) {
+	state := &funcState{
+		enclosing: c.state,
+		function:  &compiledFunction{name: name, globals: c.globals},
+		kind:      kind,
+		names:     make(map[string]int),
+	}
+
+	// Slot 0 holds the callee, or the receiver of a method.
+	receiver := ""
+	if kind == functionTypeMethod || kind == functionTypeInitializer {
+		receiver = "this"
+	}
+
+	state.locals = append(state.locals, local{name: receiver})
+	c.state = state
+}
+
+func (c *compiler) endFunction() (*compiledFunction, []upvalueRef) {
+	state := c.state
+	c.state = state.enclosing
+
+	state.function.upvalueCount = len(state.upvalues)
+
+	return state.function, state.upvalues
+}
+
+func (c *compiler) chunk() *chunk {
+	return &c.state.function.chunk
+}
+
+func (c *compiler) emit(token ast.Token, op opcode, operands ...byte) {
+	c.chunk().write(token, append([]byte{byte(op)}, operands...)...)
+}
+
+func (c *compiler) emitOperand(token ast.Token, op opcode, operand int) {
+	if operand > maxOperand {
+		c.fail(token, "Too much code to compile in one function.")
+	}
+
+	c.emit(token, op, byte(operand>>8), byte(operand))
+}
+
+func (c *compiler) fail(token ast.Token, message string) {
+	if c.err == nil {
+		c.err = errors.NewRuntimeError(token, message)
+	}
+}
+
+func (c *compiler) emitConstant(token ast.Token, value any) {
+	c.emitOperand(token, opConstant, c.chunk().addConstant(value))
+}
+
+// name returns the constant holding an identifier, each name is stored once per function.
+func (c *compiler) name(name string) int {
+	if index, ok := c.state.names[name]; ok {
+		return index
+	}
+
+	index := c.chunk().addConstant(name)
+	c.state.names[name] = index
+
+	return index
+}
+
+// emitJump emits a forward jump and returns the offset of its operand for patchJump.
+func (c *compiler) emitJump(token ast.Token, op opcode) int {
+	c.emit(token, op, 0xff, 0xff)
+	return len(c.chunk().code) - 2
+}
+
+func (c *compiler) patchJump(offset int) {
+	code := c.chunk().code
+	jump := len(code) - offset - 2
+
+	if jump > maxOperand {
+		c.fail(c.chunk().tokenAt(offset), "Too much code to jump over.")
+	}
+
+	code[offset] = byte(jump >> 8)
+	code[offset+1] = byte(jump)
+}
+
+func (c *compiler) emitLoop(token ast.Token, start int) {
+	c.emitOperand(token, opLoop, len(c.chunk().code)+3-start)
+}
+
+func (c *compiler) beginScope() {
+	c.state.scopeDepth++
+}
+
+func (c *compiler) endScope(token ast.Token) {
+	c.state.scopeDepth--
+	c.popLocals(token, c.localCountAt(c.state.scopeDepth))
+}
+
+// localCountAt returns the number of locals declared at the given depth or outside of it.
+func (c *compiler) localCountAt(depth int) int {
+	count := len(c.state.locals)
+	for count > 0 && c.state.locals[count-1].depth > depth {
+		count--
+	}
+
+	return count
+}
+
+// popLocals emits the code discarding the locals above count and forgets them.
+func (c *compiler) popLocals(token ast.Token, count int) {
+	if n := len(c.state.locals) - count; n > 0 {
+		c.emitOperand(token, opPopLocals, n)
+		c.state.locals = c.state.locals[:count]
+	}
+}
+
+// addLocal declares a local in the current scope, its value is the one on top of the stack.
+func (c *compiler) addLocal(token ast.Token, name string) {
+	if len(c.state.locals) > maxOperand {
+		c.fail(token, "Too many local variables in function.")
+	}
+
+	c.state.locals = append(c.state.locals, local{name: name, depth: c.state.scopeDepth})
+}
+
+func (c *compiler) isGlobalScope() bool {
+	return c.state.kind == functionTypeNone && c.state.scopeDepth == 0
+}
+
+// declare binds the value on top of the stack to a new variable.
+func (c *compiler) declare(name ast.Token) {
+	if c.isGlobalScope() {
+		c.emitOperand(name, opDefineGlobal, c.name(name.Lexeme))
+	}
+}
+
+func resolveLocal(state *funcState, name string) int {
+	for i := len(state.locals) - 1; i >= 0; i-- {
+		if state.locals[i].name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func resolveUpvalue(state *funcState, name string) int {
+	if state.enclosing == nil {
+		return -1
+	}
+
+	if slot := resolveLocal(state.enclosing, name); slot >= 0 {
+		return addUpvalue(state, slot, true)
+	}
+
+	if index := resolveUpvalue(state.enclosing, name); index >= 0 {
+		return addUpvalue(state, index, false)
+	}
+
+	return -1
+}
+
+func addUpvalue(state *funcState, index int, isLocal bool) int {
+	ref := upvalueRef{index: index, isLocal: isLocal}
+
+	if i := slices.Index(state.upvalues, ref); i >= 0 {
+		return i
+	}
+
+	state.upvalues = append(state.upvalues, ref)
+
+	return len(state.upvalues) - 1
+}
+
+func (c *compiler) getVariable(name ast.Token) {
+	if slot := resolveLocal(c.state, name.Lexeme); slot >= 0 {
+		c.emitOperand(name, opGetLocal, slot)
+	} else if index := resolveUpvalue(c.state, name.Lexeme); index >= 0 {
+		c.emitOperand(name, opGetUpvalue, index)
+	} else {
+		c.emitOperand(name, opGetGlobal, c.name(name.Lexeme))
+	}
+}
+
+func (c *compiler) setVariable(name ast.Token) {
+	if slot := resolveLocal(c.state, name.Lexeme); slot >= 0 {
+		c.emitOperand(name, opSetLocal, slot)
+	} else if index := resolveUpvalue(c.state, name.Lexeme); index >= 0 {
+		c.emitOperand(name, opSetUpvalue, index)
+	} else {
+		c.emitOperand(name, opSetGlobal, c.name(name.Lexeme))
+	}
+}
+
+func (c *compiler) expr(expr ast.Expr) error {
+	_, err := expr.Accept(c)
+	return err
+}
+
+func (c *compiler) stmts(stmts []ast.Stmt) error {
+	for _, stmt := range stmts {
+		if err := stmt.Accept(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *compiler) block(token ast.Token, stmts []ast.Stmt) error {
+	c.beginScope()
+
+	if err := c.stmts(stmts); err != nil {
+		return err
+	}
+
+	c.endScope(token)
+
+	return nil
+}
+
+func (c *compiler) function(decl *ast.FunctionStmt, kind functionType) error {
+	name := decl.Name.Lexeme
+	if name == "" {
+		name = "anonymous"
+	}
+
+	c.beginFunction(kind, name)
+	c.state.scopeDepth = 1
+	c.state.function.arity = len(decl.Parameters)
+
+	for _, param := range decl.Parameters {
+		c.addLocal(param, param.Lexeme)
+	}
+
+	if err := c.stmts(decl.Body); err != nil {
+		return err
+	}
+
+	c.emitReturn(decl.Name, nil)
+
+	fn, upvalues := c.endFunction()
+
+	c.emitOperand(decl.Name, opClosure, c.chunk().addConstant(fn))
+
+	for _, ref := range upvalues {
+		c.chunk().write(decl.Name, byte(boolToInt(ref.isLocal)), byte(ref.index>>8), byte(ref.index))
+	}
+
+	return nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// emitReturn returns the given value, or the default one of the function, running the
+// finally blocks of the enclosing try statements first.
+func (c *compiler) emitReturn(token ast.Token, value ast.Expr) error {
+	switch {
+	case c.state.kind == functionTypeInitializer:
+		c.emitOperand(token, opGetLocal, 0)
+	case value != nil:
+		if err := c.expr(value); err != nil {
+			return err
+		}
+	default:
+		c.emit(token, opNil)
+	}
+
+	if err := c.leaveTries(token, 0, true); err != nil {
+		return err
+	}
+
+	c.emit(token, opReturn)
+
+	return nil
+}
+
+// leaveTries emits the code for a jump out of the try regions above tryCount. When keepTop
+// is set the value on top of the stack, e.g. the one being returned, is kept.
+func (c *compiler) leaveTries(token ast.Token, tryCount int, keepTop bool) error {
+	state := c.state
+
+	for i := len(state.tries) - 1; i >= tryCount; i-- {
+		try := state.tries[i]
+
+		if n := len(state.locals) - try.localCount; n > 0 {
+			c.emitOperand(token, popOp(keepTop), n)
+			state.locals = state.locals[:try.localCount]
+		}
+
+		if try.handler {
+			c.emit(token, opEndTry)
+		}
+
+		if try.finally == nil {
+			continue
+		}
+
+		locals, scopeDepth, loops, tries := state.locals, state.scopeDepth, state.loops, state.tries
+
+		// The finally block sees the scope of the try statement, the value being kept
+		// occupies a slot of its own.
+		state.locals = locals[:len(locals):len(locals)]
+		state.scopeDepth = try.scopeDepth
+		state.loops = loops[:try.loopCount:try.loopCount]
+		state.tries = tries[:i:i]
+
+		if keepTop {
+			c.beginScope()
+			c.addLocal(token, "")
+		}
+
+		err := c.block(token, try.finally)
+
+		state.locals, state.scopeDepth, state.loops, state.tries = locals, scopeDepth, loops, tries
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func popOp(keepTop bool) opcode {
+	if keepTop {
+		return opPopBelow
+	}
+
+	return opPopLocals
+}
+
+func (c *compiler) VisitPrintStmt(stmt *ast.PrintStmt) error {
+	if err := c.expr(stmt.Expr); err != nil {
+		return err
+	}
+
+	c.emit(ast.Token{}, opPrint)
+
+	return nil
+}
+
+func (c *compiler) VisitExprStmt(stmt *ast.ExprStmt) error {
+	if err := c.expr(stmt.Expr); err != nil {
+		return err
+	}
+
+	c.emit(ast.Token{}, opPop)
+
+	return nil
+}
+
+func (c *compiler) VisitVarStmt(stmt *ast.VarStmt) error {
+	// A local is declared before its initializer, so functions in it can refer to it.
+	if !c.isGlobalScope() {
+		c.addLocal(stmt.Name, stmt.Name.Lexeme)
+	}
+
+	if stmt.Initializer != nil {
+		if err := c.expr(stmt.Initializer); err != nil {
+			return err
+		}
+	} else {
+		c.emit(stmt.Name, opNil)
+	}
+
+	c.declare(stmt.Name)
+
+	return nil
+}
+
+func (c *compiler) VisitBlockStmt(stmt *ast.BlockStmt) error {
+	return c.block(ast.Token{}, stmt.Stmts)
+}
+
+func (c *compiler) VisitIfStmt(stmt *ast.IfStmt) error {
+	if err := c.expr(stmt.Condition); err != nil {
+		return err
+	}
+
+	elseJump := c.emitJump(ast.Token{}, opJumpIfFalse)
+	c.emit(ast.Token{}, opPop)
+
+	if err := stmt.Then.Accept(c); err != nil {
+		return err
+	}
+
+	endJump := c.emitJump(ast.Token{}, opJump)
+
+	c.patchJump(elseJump)
+	c.emit(ast.Token{}, opPop)
+
+	if stmt.El != nil {
+		if err := stmt.El.Accept(c); err != nil {
+			return err
+		}
+	}
+
+	c.patchJump(endJump)
+
+	return nil
+}
+
+func (c *compiler) VisitWhileStmt(stmt *ast.WhileStmt) error {
+	loopStart := len(c.chunk().code)
+
+	if err := c.expr(stmt.Condition); err != nil {
+		return err
+	}
+
+	exitJump := c.emitJump(ast.Token{}, opJumpIfFalse)
+	c.emit(ast.Token{}, opPop)
+
+	loop := &loopState{
+		label:      stmt.Label.Lexeme,
+		localCount: len(c.state.locals),
+		tryCount:   len(c.state.tries),
+	}
+
+	c.state.loops = append(c.state.loops, loop)
+
+	err := stmt.Body.Accept(c)
+
+	c.state.loops = c.state.loops[:len(c.state.loops)-1]
+
+	if err != nil {
+		return err
+	}
+
+	for _, jump := range loop.continues {
+		c.patchJump(jump)
+	}
+
+	if stmt.Increment != nil {
+		if err := c.expr(stmt.Increment); err != nil {
+			return err
+		}
+
+		c.emit(ast.Token{}, opPop)
+	}
+
+	c.emitLoop(stmt.Keyword, loopStart)
+
+	c.patchJump(exitJump)
+	c.emit(ast.Token{}, opPop)
+
+	for _, jump := range loop.breaks {
+		c.patchJump(jump)
+	}
+
+	return nil
+}
+
+// targetLoop finds the loop a break or continue jumps to, the resolver has already
+// checked that it exists.
+func (c *compiler) targetLoop(label ast.Token) *loopState {
+	loops := c.state.loops
+
+	if label.Lexeme == "" {
+		return loops[len(loops)-1]
+	}
+
+	for i := len(loops) - 1; i >= 0; i-- {
+		if loops[i].label == label.Lexeme {
+			return loops[i]
+		}
+	}
+
+	return loops[len(loops)-1]
+}
+
+func (c *compiler) jumpOutOf(keyword ast.Token, loop *loopState) (int, error) {
+	locals := c.state.locals
+
+	if err := c.leaveTries(keyword, loop.tryCount, false); err != nil {
+		return 0, err
+	}
+
+	c.popLocals(keyword, loop.localCount)
+
+	// The code after the jump is still in the scope of the jump.
+	c.state.locals = locals
+
+	return c.emitJump(keyword, opJump), nil
+}
+
+func (c *compiler) VisitBreakStmt(stmt *ast.BreakStmt) error {
+	loop := c.targetLoop(stmt.Label)
+
+	jump, err := c.jumpOutOf(stmt.Keyword, loop)
+	loop.breaks = append(loop.breaks, jump)
+
+	return err
+}
+
+func (c *compiler) VisitContinueStmt(stmt *ast.ContinueStmt) error {
+	loop := c.targetLoop(stmt.Label)
+
+	jump, err := c.jumpOutOf(stmt.Keyword, loop)
+	loop.continues = append(loop.continues, jump)
+
+	return err
+}
+
+func (c *compiler) VisitFunctionStmt(stmt *ast.FunctionStmt) error {
+	if !c.isGlobalScope() {
+		c.addLocal(stmt.Name, stmt.Name.Lexeme)
+	}
+
+	if err := c.function(stmt, functionTypeFunction); err != nil {
+		return err
+	}
+
+	c.declare(stmt.Name)
+
+	return nil
+}
+
+func (c *compiler) VisitReturnStmt(stmt *ast.ReturnStmt) error {
+	locals := c.state.locals
+
+	err := c.emitReturn(stmt.Keyword, stmt.Value)
+	c.state.locals = locals
+
+	return err
+}
+
+func (c *compiler) VisitClassStmt(stmt *ast.ClassStmt) error {
+	if !c.isGlobalScope() {
+		c.addLocal(stmt.Name, stmt.Name.Lexeme)
+	}
+
+	c.emitOperand(stmt.Name, opClass, c.name(stmt.Name.Lexeme))
+	c.declare(stmt.Name)
+
+	c.getVariable(stmt.Name)
+
+	for _, method := range stmt.Methods {
+		kind := functionTypeMethod
+		if method.Name.Lexeme == callable.InitializerName {
+			kind = functionTypeInitializer
+		}
+
+		if err := c.function(method, kind); err != nil {
+			return err
+		}
+
+		c.emitOperand(method.Name, opMethod, c.name(method.Name.Lexeme))
+	}
+
+	c.emit(stmt.Name, opPop)
+
+	return nil
+}
+
+func (c *compiler) VisitTryStmt(stmt *ast.TryStmt) error {
+	state := c.state
+	try := &tryState{
+		localCount: len(state.locals),
+		scopeDepth: state.scopeDepth,
+		loopCount:  len(state.loops),
+		handler:    true,
+		finally:    stmt.FinallyBody,
+	}
+
+	handlerJump := c.emitJump(stmt.Keyword, opTry)
+
+	if err := c.protected(try, func() error { return c.block(stmt.Keyword, stmt.Body) }); err != nil {
+		return err
+	}
+
+	c.emit(stmt.Keyword, opEndTry)
+
+	if err := c.finally(stmt); err != nil {
+		return err
+	}
+
+	endJumps := []int{c.emitJump(stmt.Keyword, opJump)}
+
+	// The handler starts with the error on top of the stack.
+	c.patchJump(handlerJump)
+
+	var rethrowJumps []int
+
+	if stmt.CatchBody != nil {
+		rethrowJumps = append(rethrowJumps, c.emitJump(stmt.Keyword, opCatch))
+
+		c.beginScope()
+		c.addLocal(stmt.CatchName, stmt.CatchName.Lexeme)
+
+		catch := *try
+		catch.handler = stmt.FinallyBody != nil
+
+		var catchHandler int
+		if catch.handler {
+			catchHandler = c.emitJump(stmt.Keyword, opTry)
+		}
+
+		if err := c.protected(&catch, func() error { return c.stmts(stmt.CatchBody) }); err != nil {
+			return err
+		}
+
+		if catch.handler {
+			c.emit(stmt.Keyword, opEndTry)
+		}
+
+		c.endScope(stmt.Keyword)
+
+		if err := c.finally(stmt); err != nil {
+			return err
+		}
+
+		endJumps = append(endJumps, c.emitJump(stmt.Keyword, opJump))
+
+		// An error in the catch block drops the caught value and rethrows after finally.
+		if catch.handler {
+			c.patchJump(catchHandler)
+			c.emitOperand(stmt.Keyword, opPopBelow, 1)
+		}
+	}
+
+	for _, jump := range rethrowJumps {
+		c.patchJump(jump)
+	}
+
+	if stmt.FinallyBody != nil {
+		locals := state.locals
+
+		c.beginScope()
+		c.addLocal(stmt.Keyword, "")
+
+		if err := c.finally(stmt); err != nil {
+			return err
+		}
+
+		state.scopeDepth--
+		state.locals = locals
+	}
+
+	c.emit(stmt.Keyword, opRethrow)
+
+	for _, jump := range endJumps {
+		c.patchJump(jump)
+	}
+
+	return nil
+}
+
+// protected compiles code inside a try region.
+func (c *compiler) protected(try *tryState, compile func() error) error {
+	c.state.tries = append(c.state.tries, try)
+	err := compile()
+	c.state.tries = c.state.tries[:len(c.state.tries)-1]
+
+	return err
+}
+
+func (c *compiler) finally(stmt *ast.TryStmt) error {
+	if stmt.FinallyBody == nil {
+		return nil
+	}
+
+	return c.block(stmt.Keyword, stmt.FinallyBody)
+}
+
+func (c *compiler) VisitThrowStmt(stmt *ast.ThrowStmt) error {
+	if err := c.expr(stmt.Value); err != nil {
+		return err
+	}
+
+	c.emit(stmt.Keyword, opThrow)
+
+	return nil
+}
+
+func (c *compiler) VisitImportStmt(stmt *ast.ImportStmt) error {
+	c.emitOperand(stmt.Keyword, opImport, c.chunk().addConstant(stmt))
+	return nil
+}
+
+func (c *compiler) VisitExportStmt(stmt *ast.ExportStmt) error {
+	if err := stmt.Declaration.Accept(c); err != nil {
+		return err
+	}
+
+	c.emitOperand(stmt.Name, opExport, c.name(stmt.Name.Lexeme))
+
+	return nil
+}
+
+func (c *compiler) VisitTestStmt(stmt *ast.TestStmt) error {
+	if err := c.function(stmt.Function, functionTypeFunction); err != nil {
+		return err
+	}
+
+	c.emitOperand(stmt.Keyword, opTest, c.chunk().addConstant(stmt))
+
+	return nil
+}
+
+func (c *compiler) VisitBinaryExpr(expr *ast.BinaryExpr) (any, error) {
+	if err := c.expr(expr.Left); err != nil {
+		return nil, err
+	}
+
+	if err := c.expr(expr.Right); err != nil {
+		return nil, err
+	}
+
+	op, ok := binaryOps[expr.Operator.TokenType]
+	if !ok {
+		c.fail(expr.Operator, "Unknown operator.")
+	}
+
+	c.emit(expr.Operator, op)
+
+	return nil, nil
+}
+
+var binaryOps = map[ast.TokenType]opcode{
+	ast.EQUAL_EQUAL:   opEqual,
+	ast.BANG_EQUAL:    opNotEqual,
+	ast.GREATER:       opGreater,
+	ast.GREATER_EQUAL: opGreaterEqual,
+	ast.LESS:          opLess,
+	ast.LESS_EQUAL:    opLessEqual,
+	ast.PLUS:          opAdd,
+	ast.MINUS:         opSubtract,
+	ast.STAR:          opMultiply,
+	ast.SLASH:         opDivide,
+}
+
+func (c *compiler) VisitLiteralExpr(expr *ast.LiteralExpr) (any, error) {
+	switch value := expr.Value.(type) {
+	case nil:
+		c.emit(ast.Token{}, opNil)
+	case bool:
+		if value {
+			c.emit(ast.Token{}, opTrue)
+		} else {
+			c.emit(ast.Token{}, opFalse)
+		}
+	default:
+		c.emitConstant(ast.Token{}, value)
+	}
+
+	return nil, nil
+}
+
+func (c *compiler) VisitGroupingExpr(expr *ast.GroupingExpr) (any, error) {
+	return nil, c.expr(expr.Expr)
+}
+
+func (c *compiler) VisitUnaryExpr(expr *ast.UnaryExpr) (any, error) {
+	if err := c.expr(expr.Right); err != nil {
+		return nil, err
+	}
+
+	switch expr.Operator.TokenType {
+	case ast.BANG:
+		c.emit(expr.Operator, opNot)
+	case ast.MINUS:
+		c.emit(expr.Operator, opNegate)
+	}
+
+	return nil, nil
+}
+
+func (c *compiler) VisitVarExpr(expr *ast.VarExpr) (any, error) {
+	c.getVariable(expr.Name)
+	return nil, nil
+}
+
+func (c *compiler) VisitThisExpr(expr *ast.ThisExpr) (any, error) {
+	c.getVariable(expr.Keyword)
+	return nil, nil
+}
+
+func (c *compiler) VisitAssignExpr(expr *ast.AssignExpr) (any, error) {
+	if err := c.expr(expr.Value); err != nil {
+		return nil, err
+	}
+
+	c.setVariable(expr.Name)
+
+	return nil, nil
+}
+
+func (c *compiler) VisitLogicalExpr(expr *ast.LogicalExpr) (any, error) {
+	if err := c.expr(expr.Left); err != nil {
+		return nil, err
+	}
+
+	if expr.Op.TokenType == ast.OR {
+		elseJump := c.emitJump(expr.Op, opJumpIfFalse)
+		endJump := c.emitJump(expr.Op, opJump)
+
+		c.patchJump(elseJump)
+		c.emit(expr.Op, opPop)
+
+		if err := c.expr(expr.Right); err != nil {
+			return nil, err
+		}
+
+		c.patchJump(endJump)
+
+		return nil, nil
+	}
+
+	endJump := c.emitJump(expr.Op, opJumpIfFalse)
+	c.emit(expr.Op, opPop)
+
+	if err := c.expr(expr.Right); err != nil {
+		return nil, err
+	}
+
+	c.patchJump(endJump)
+
+	return nil, nil
+}
+
+func (c *compiler) VisitCallExpr(expr *ast.CallExpr) (any, error) {
+	if err := c.expr(expr.Callee); err != nil {
+		return nil, err
+	}
+
+	for _, arg := range expr.Args {
+		if err := c.expr(arg); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(expr.Args) > 255 {
+		c.fail(expr.Token, "Too many arguments.")
+	}
+
+	c.emit(expr.Token, opCall, byte(len(expr.Args)))
+
+	return nil, nil
+}
+
+func (c *compiler) VisitArrayExpr(expr *ast.ArrayExpr) (any, error) {
+	for _, item := range expr.Items {
+		if err := c.expr(item); err != nil {
+			return nil, err
+		}
+	}
+
+	c.emitOperand(expr.Token, opArray, len(expr.Items))
+
+	return nil, nil
+}
+
+func (c *compiler) VisitIndexExpr(expr *ast.IndexExpr) (any, error) {
+	if err := c.expr(expr.Array); err != nil {
+		return nil, err
+	}
+
+	if err := c.expr(expr.Index); err != nil {
+		return nil, err
+	}
+
+	c.emit(expr.Token, opGetIndex)
+
+	return nil, nil
+}
+
+func (c *compiler) VisitSetIndexExpr(expr *ast.SetIndexExpr) (any, error) {
+	for _, e := range []ast.Expr{expr.Array, expr.Index, expr.Value} {
+		if err := c.expr(e); err != nil {
+			return nil, err
+		}
+	}
+
+	c.emit(expr.Token, opSetIndex)
+
+	return nil, nil
+}
+
+func (c *compiler) VisitObjectExpr(expr *ast.ObjectExpr) (any, error) {
+	for _, pair := range expr.Pairs {
+		c.emitConstant(ast.Token{}, pair.Key.Lexeme)
+
+		if err := c.expr(pair.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	c.emitOperand(expr.Token, opObject, len(expr.Pairs))
+
+	return nil, nil
+}
+
+func (c *compiler) VisitGetExpr(expr *ast.GetExpr) (any, error) {
+	if err := c.expr(expr.Object); err != nil {
+		return nil, err
+	}
+
+	c.emitOperand(expr.Name, opGetProperty, c.name(expr.Name.Lexeme))
+
+	return nil, nil
+}
+
+func (c *compiler) VisitSetExpr(expr *ast.SetExpr) (any, error) {
+	if err := c.expr(expr.Object); err != nil {
+		return nil, err
+	}
+
+	if err := c.expr(expr.Value); err != nil {
+		return nil, err
+	}
+
+	c.emitOperand(expr.Name, opSetProperty, c.name(expr.Name.Lexeme))
+
+	return nil, nil
+}
+
+func (c *compiler) VisitFunctionExpr(expr *ast.FunctionExpr) (any, error) {
+	return nil, c.function(expr.Declaration, functionTypeFunction)
+}
@@ -0,0 +1,73 @@
+package rune
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"rune/pkg/ast"
+	"rune/pkg/errors"
+)
+
+// Format returns a program in its canonical layout, see ast.Format. Programs that do not
+// scan or parse are returned as a CompileError. The formatted program is parsed again
+// and must be the same program with the same comments, so a bug in the formatter cannot
+// change what a file does.
+func Format(source []byte) ([]byte, error) {
+	stmts, comments, err := parseWithComments(source)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted := ast.Format(stmts, comments)
+
+	again, againComments, err := parseWithComments([]byte(formatted))
+	if err != nil {
+		return nil, fmt.Errorf("formatting produced an invalid program: %v", err)
+	}
+
+	if printStmts(again) != printStmts(stmts) {
+		return nil, fmt.Errorf("formatting changed the program")
+	}
+
+	if !slices.Equal(commentTexts(againComments), commentTexts(comments)) {
+		return nil, fmt.Errorf("formatting changed the comments")
+	}
+
+	return []byte(formatted), nil
+}
+
+func parseWithComments(source []byte) ([]ast.Stmt, []ast.Comment, error) {
+	tokens, comments, scanErrors := ScanWithComments(source)
+	if len(scanErrors) > 0 {
+		return nil, nil, errors.NewCompileError(scanErrors)
+	}
+
+	stmts, parseErrors := ParseStmts(tokens)
+	if len(parseErrors) > 0 {
+		return nil, nil, errors.NewCompileError(parseErrors)
+	}
+
+	return stmts, comments, nil
+}
+
+func printStmts(stmts []ast.Stmt) string {
+	printer := ast.NewPrinter()
+
+	var sb strings.Builder
+	for _, stmt := range stmts {
+		sb.WriteString(printer.PrintStmt(stmt))
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+func commentTexts(comments []ast.Comment) []string {
+	texts := make([]string, len(comments))
+	for i, comment := range comments {
+		texts[i] = strings.TrimRight(comment.Text, " \t")
+	}
+
+	return texts
+}
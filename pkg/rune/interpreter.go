@@ -2,45 +2,97 @@ package rune
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"rune/pkg/ast"
 	"rune/pkg/callable"
 	"rune/pkg/environment"
 	"rune/pkg/errors"
 	"rune/pkg/helpers"
+	"rune/pkg/sandbox"
 )
 
 const maxRecursionDepth = 999
 
+// loopBreak is a special type of error that unwinds to the loop being broken out of.
+type loopBreak struct {
+	label string
+}
+
+func (e *loopBreak) Error() string {
+	return "<loop break>"
+}
+
+// loopContinue is a special type of error that unwinds to the loop being continued.
+type loopContinue struct {
+	label string
+}
+
+func (e *loopContinue) Error() string {
+	return "<loop continue>"
+}
+
+// frame is an active call, the name of the callee and the line it was called from.
+// A call made by the host has no caller in the script, its trace ends with it.
+type frame struct {
+	name     string
+	callLine int
+	fromHost bool
+}
+
+// localSlot locates a local variable: the number of scopes out it was declared in
+// and its slot in the environment of that scope.
+type localSlot struct {
+	distance int
+	slot     int
+}
+
+// traced is implemented by the errors that carry a stack trace.
+type traced interface {
+	error
+	Line() int
+	Trace() []errors.Frame
+	WithTrace(trace []errors.Frame) error
+}
+
 type Interpreter struct {
-	environment    *environment.Environment
-	globals        *environment.Environment
-	locals         map[ast.Expr]int
-	recursionDepth int
-	maxRecursion   int
+	environment  *environment.Environment
+	natives      map[string]callable.Callable
+	locals       map[ast.Expr]localSlot
+	frames       []frame
+	maxRecursion int
+	loader       *moduleLoader
+	// out receives the output of print statements.
+	out    io.Writer
+	limits *limiter
+	// permissions are the capabilities granted to native functions, none by default.
+	permissions sandbox.Permissions
+	// tests collects the test blocks of the script, it is nil unless they are run.
+	tests *testSuite
 }
 
 func NewInterpreter() *Interpreter {
-	globals := environment.NewEnvironment(nil)
-
 	p := &Interpreter{
-		environment:    globals,
-		globals:        globals,
-		locals:         make(map[ast.Expr]int),
-		recursionDepth: 0,
-		maxRecursion:   maxRecursionDepth,
+		out:          os.Stdout,
+		limits:       newLimiter(Limits{}),
+		environment:  environment.NewEnvironment(nil),
+		natives:      make(map[string]callable.Callable),
+		locals:       make(map[ast.Expr]localSlot),
+		maxRecursion: maxRecursionDepth,
 	}
 
+	p.loader = newModuleLoader(p.natives, p, p.runModule)
+
 	// Global functions.
-	p.registerGlobalCallable("clock", callable.NewClockCallable())
-	p.registerGlobalCallable("len", callable.NewLenCallable())
-	p.registerGlobalCallable("append", callable.NewAppendCallable())
-	p.registerGlobalCallable("json", callable.NewJsonCallable())
+	for name, fn := range Builtins() {
+		p.registerGlobalCallable(name, fn)
+	}
 
 	return p
 }
 
 func EvaluateExpr(expr ast.Expr) (any, error) {
-	return expr.Accept(&Interpreter{})
+	return expr.Accept(&Interpreter{limits: newLimiter(Limits{})})
 }
 
 func (p *Interpreter) EvaluateStmts(stmts []ast.Stmt) error {
@@ -54,7 +106,38 @@ func (p *Interpreter) EvaluateStmts(stmts []ast.Stmt) error {
 	return nil
 }
 
+// Evaluate evaluates a single expression in the current environment.
+func (p *Interpreter) Evaluate(expr ast.Expr) (any, error) {
+	return expr.Accept(p)
+}
+
+// Globals returns the global environment of the script being run.
+func (p *Interpreter) Globals() *environment.Environment {
+	return p.environment.Globals()
+}
+
+// SetOutput redirects the output of print statements, which goes to os.Stdout by default.
+func (p *Interpreter) SetOutput(w io.Writer) {
+	p.out = w
+}
+
+func (p *Interpreter) setLimiter(l *limiter) {
+	p.limits = l
+}
+
+// SetPermissions grants capabilities to native functions that access the network,
+// files or the environment, and to imports of modules outside the script's directory.
+func (p *Interpreter) SetPermissions(permissions sandbox.Permissions) {
+	p.permissions = permissions
+	p.loader.permissions = permissions
+}
+
+func (p *Interpreter) setTestSuite(suite *testSuite) {
+	p.tests = suite
+}
+
 func (p *Interpreter) registerGlobalCallable(name string, value callable.Callable) {
+	p.natives[name] = value
 	p.environment.Define(name, value)
 }
 
@@ -89,23 +172,16 @@ func (p *Interpreter) VisitPrintStmt(exprStmt *ast.PrintStmt) error {
 		return err
 	}
 
-	if val == nil {
-		fmt.Println("nil")
-		return nil
-	}
-
-	if v, ok := val.(float64); ok {
-		if v == float64(int64(v)) {
-			fmt.Println(fmt.Sprintf("%.0f", v))
-			return nil
-		}
-	}
-
-	fmt.Println(val)
+	fmt.Fprintln(p.out, Stringify(val))
 
 	return nil
 }
 
+// Stringify renders a runtime value the way print shows it, see callable.Stringify.
+func Stringify(val any) string {
+	return callable.Stringify(val)
+}
+
 func (p *Interpreter) VisitLogicalExpr(node *ast.LogicalExpr) (any, error) {
 	left, err := node.Left.Accept(p)
 
@@ -127,6 +203,8 @@ func (p *Interpreter) VisitLogicalExpr(node *ast.LogicalExpr) (any, error) {
 }
 
 func (p *Interpreter) VisitWhileStmt(whileStmt *ast.WhileStmt) error {
+	label := whileStmt.Label.Lexeme
+
 	val, err := whileStmt.Condition.Accept(p)
 	if err != nil {
 		return err
@@ -134,10 +212,33 @@ func (p *Interpreter) VisitWhileStmt(whileStmt *ast.WhileStmt) error {
 
 	for helpers.IsTruthy(val) {
 		err := whileStmt.Body.Accept(p)
-		if err != nil {
+
+		switch jump := err.(type) {
+		case nil:
+		case *loopBreak:
+			if jump.label == "" || jump.label == label {
+				return nil
+			}
+
+			return err
+		case *loopContinue:
+			if jump.label != "" && jump.label != label {
+				return err
+			}
+		default:
 			return err
 		}
 
+		if whileStmt.Increment != nil {
+			if _, err := whileStmt.Increment.Accept(p); err != nil {
+				return err
+			}
+		}
+
+		if !p.limits.step() {
+			return p.limits.exceeded(whileStmt.Keyword)
+		}
+
 		val, err = whileStmt.Condition.Accept(p)
 		if err != nil {
 			return err
@@ -147,6 +248,48 @@ func (p *Interpreter) VisitWhileStmt(whileStmt *ast.WhileStmt) error {
 	return nil
 }
 
+func (p *Interpreter) VisitTryStmt(tryStmt *ast.TryStmt) error {
+	err := p.executeBlock(tryStmt.Body, environment.NewEnvironment(p.environment))
+
+	if err != nil && tryStmt.CatchBody != nil {
+		if caught, ok := caughtValue(err); ok {
+			env := environment.NewEnvironment(p.environment)
+
+			if tryStmt.CatchName.Lexeme != "" {
+				env.Define(tryStmt.CatchName.Lexeme, caught)
+			}
+
+			err = p.executeBlock(tryStmt.CatchBody, env)
+		}
+	}
+
+	if tryStmt.FinallyBody != nil {
+		// An error or jump out of the finally block replaces whatever was pending.
+		if finallyErr := p.executeBlock(tryStmt.FinallyBody, environment.NewEnvironment(p.environment)); finallyErr != nil {
+			return finallyErr
+		}
+	}
+
+	return err
+}
+
+func (p *Interpreter) VisitThrowStmt(throwStmt *ast.ThrowStmt) error {
+	value, err := throwStmt.Value.Accept(p)
+	if err != nil {
+		return err
+	}
+
+	return errors.NewThrownError(throwStmt.Keyword, value)
+}
+
+func (p *Interpreter) VisitBreakStmt(breakStmt *ast.BreakStmt) error {
+	return &loopBreak{label: breakStmt.Label.Lexeme}
+}
+
+func (p *Interpreter) VisitContinueStmt(continueStmt *ast.ContinueStmt) error {
+	return &loopContinue{label: continueStmt.Label.Lexeme}
+}
+
 func (p *Interpreter) VisitVarStmt(varStmt *ast.VarStmt) error {
 	if varStmt.Initializer != nil {
 		value, err := varStmt.Initializer.Accept(p)
@@ -164,13 +307,6 @@ func (p *Interpreter) VisitVarStmt(varStmt *ast.VarStmt) error {
 }
 
 func (p *Interpreter) VisitCallExpr(callExpr *ast.CallExpr) (any, error) {
-	if p.recursionDepth >= p.maxRecursion {
-		return nil, errors.NewRuntimeError(callExpr.Token, "Stack overflow.")
-	}
-
-	p.recursionDepth++
-	defer func() { p.recursionDepth-- }()
-
 	callee, err := callExpr.Callee.Accept(p)
 	if err != nil {
 		return nil, err
@@ -194,14 +330,94 @@ func (p *Interpreter) VisitCallExpr(callExpr *ast.CallExpr) (any, error) {
 			)
 		}
 
-		return callable.Call(p.executeBlock, args, callExpr.Token)
+		return p.call(callable, args, callExpr.Token, false)
 	}
 
 	return nil, errors.NewRuntimeError(callExpr.Token, "Can only call functions.")
 }
 
+// call runs a callable in a new frame. An error leaving the innermost frame gets the
+// stack trace of the frames active at that point.
+func (p *Interpreter) call(fn callable.Callable, args []any, token ast.Token, fromHost bool) (any, error) {
+	if !p.limits.step() {
+		return nil, p.limits.exceeded(token)
+	}
+
+	if len(p.frames) >= p.maxRecursion {
+		return nil, errors.NewRuntimeError(token, "Stack overflow.")
+	}
+
+	p.frames = append(p.frames, frame{name: frameName(fn), callLine: token.Line, fromHost: fromHost})
+	defer func() { p.frames = p.frames[:len(p.frames)-1] }()
+
+	err := authorize(p.permissions, fn, args, token)
+
+	var result any
+	if err == nil {
+		result, err = callNative(fn, p.executeBlock, p.callBack, args, token)
+	}
+
+	if err == nil && !p.limits.allocateResult(fn, args, result) {
+		err = p.limits.exceeded(token)
+	}
+
+	if e, ok := err.(traced); ok && e.Trace() == nil {
+		return nil, e.WithTrace(p.trace(e.Line()))
+	}
+
+	return result, err
+}
+
+// callBack calls a function passed to a native function, as a frame of the script.
+func (p *Interpreter) callBack(fn callable.Callable, args []any, token ast.Token) (any, error) {
+	return p.call(fn, args, token, false)
+}
+
+// callFunction calls a function value from the host, the arguments have been checked
+// against its arity.
+func (p *Interpreter) callFunction(fn any, args []any) (any, error) {
+	return p.call(fn.(callable.Callable), args, ast.Token{}, true)
+}
+
+// arity returns the number of arguments a function value expects, -1 for any number.
+func (p *Interpreter) arity(fn any) (int, bool) {
+	if callee, ok := fn.(callable.Callable); ok {
+		return callee.Arity(), true
+	}
+
+	return 0, false
+}
+
+// trace lists the active frames innermost first, each with the line it is at. The
+// innermost frame is at the line of the error, the others at the line of their call.
+func (p *Interpreter) trace(line int) []errors.Frame {
+	trace := make([]errors.Frame, 0, len(p.frames)+1)
+
+	for i := len(p.frames) - 1; i >= 0; i-- {
+		trace = append(trace, errors.Frame{Function: p.frames[i].name, Line: line})
+		line = p.frames[i].callLine
+
+		if p.frames[i].fromHost {
+			return trace
+		}
+	}
+
+	return append(trace, errors.Frame{Function: errors.ScriptFrame, Line: line})
+}
+
+func frameName(fn callable.Callable) string {
+	switch f := fn.(type) {
+	case *callable.ClassCallable:
+		return f.Name
+	case callable.Named:
+		return f.Name()
+	default:
+		return "native"
+	}
+}
+
 func (p *Interpreter) VisitFunctionStmt(functionStmt *ast.FunctionStmt) error {
-	function := callable.NewFunctionCallable(functionStmt, p.environment)
+	function := callable.NewFunctionCallable(functionStmt, p.environment, false)
 
 	if len(function.Declaration.Name.Lexeme) == 0 {
 		return errors.NewRuntimeError(functionStmt.Name, "Function name is required.")
@@ -211,6 +427,34 @@ func (p *Interpreter) VisitFunctionStmt(functionStmt *ast.FunctionStmt) error {
 	return nil
 }
 
+func (p *Interpreter) VisitFunctionExpr(node *ast.FunctionExpr) (any, error) {
+	return callable.NewFunctionCallable(node.Declaration, p.environment, false), nil
+}
+
+// VisitTestStmt collects a test block when the tests of the script are run, it does
+// nothing otherwise.
+func (p *Interpreter) VisitTestStmt(testStmt *ast.TestStmt) error {
+	if p.tests != nil {
+		p.tests.add(p.loader, testStmt, callable.NewFunctionCallable(testStmt.Function, p.environment, false))
+	}
+
+	return nil
+}
+
+func (p *Interpreter) VisitClassStmt(classStmt *ast.ClassStmt) error {
+	methods := make(map[string]*callable.FunctionCallable)
+
+	for _, method := range classStmt.Methods {
+		isInitializer := method.Name.Lexeme == callable.InitializerName
+		methods[method.Name.Lexeme] = callable.NewFunctionCallable(method, p.environment, isInitializer)
+	}
+
+	// The methods only refer to the class once they run, so it can be defined last.
+	p.environment.Define(classStmt.Name.Lexeme, callable.NewClassCallable(classStmt.Name.Lexeme, methods))
+
+	return nil
+}
+
 func (p *Interpreter) VisitIfStmt(ifStmt *ast.IfStmt) error {
 	condition, err := ifStmt.Condition.Accept(p)
 	if err != nil {
@@ -252,18 +496,16 @@ func (p *Interpreter) VisitVarExpr(node *ast.VarExpr) (any, error) {
 	return p.lookupVariable(node.Name, node)
 }
 
-func (p *Interpreter) lookupVariable(name ast.Token, expr ast.Expr) (any, error) {
-	if distance, ok := p.GetLocalDistance(expr); ok {
-		return p.environment.GetAt(distance, name.Lexeme), nil
-	}
-
-	return p.globals.Get(name)
+func (p *Interpreter) VisitThisExpr(node *ast.ThisExpr) (any, error) {
+	return p.lookupVariable(node.Keyword, node)
 }
 
-func (p *Interpreter) GetLocalDistance(expr ast.Expr) (int, bool) {
-	distance, ok := p.locals[expr]
+func (p *Interpreter) lookupVariable(name ast.Token, expr ast.Expr) (any, error) {
+	if local, ok := p.locals[expr]; ok {
+		return p.environment.GetAt(local.distance, local.slot), nil
+	}
 
-	return distance, ok
+	return p.environment.Globals().Get(name)
 }
 
 func (p *Interpreter) VisitAssignExpr(node *ast.AssignExpr) (any, error) {
@@ -272,11 +514,10 @@ func (p *Interpreter) VisitAssignExpr(node *ast.AssignExpr) (any, error) {
 		return nil, err
 	}
 
-	distance, ok := p.GetLocalDistance(node)
-	if ok {
-		p.environment.AssignAt(distance, node.Name.Lexeme, value)
+	if local, ok := p.locals[node]; ok {
+		p.environment.AssignAt(local.distance, local.slot, value)
 	} else {
-		if err := p.globals.Assign(node.Name, value); err != nil {
+		if err := p.environment.Globals().Assign(node.Name, value); err != nil {
 			return nil, err
 		}
 	}
@@ -286,65 +527,21 @@ func (p *Interpreter) VisitAssignExpr(node *ast.AssignExpr) (any, error) {
 
 func (p *Interpreter) VisitBinaryExpr(node *ast.BinaryExpr) (any, error) {
 	left, err := node.Left.Accept(p)
-	right, err := node.Right.Accept(p)
-
 	if err != nil {
 		return nil, err
 	}
 
-	switch node.Operator.TokenType {
-	case ast.EQUAL_EQUAL:
-		return helpers.IsEqual(left, right), nil
-	case ast.BANG_EQUAL:
-		return !helpers.IsEqual(left, right), nil
-	case ast.PLUS:
-		if helpers.IsString(left) && helpers.IsString(right) {
-			return left.(string) + right.(string), nil
-		}
-
-		if helpers.IsFloat(left) && helpers.IsFloat(right) {
-			return left.(float64) + right.(float64), nil
-		}
+	right, err := node.Right.Accept(p)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil, errors.NewRuntimeError(node.Operator, "Operands must be two numbers or two strings.")
-	case ast.MINUS:
-		if err := p.checkNumberOperands(left, right); err != nil {
-			return nil, errors.NewRuntimeError(node.Operator, err.Error())
-		}
-		return helpers.ToFloat(left) - helpers.ToFloat(right), nil
-	case ast.SLASH:
-		if err := p.checkNumberOperands(left, right); err != nil {
-			return nil, errors.NewRuntimeError(node.Operator, err.Error())
-		}
-		return helpers.ToFloat(left) / helpers.ToFloat(right), nil
-	case ast.STAR:
-		if err := p.checkNumberOperands(left, right); err != nil {
-			return nil, errors.NewRuntimeError(node.Operator, err.Error())
-		}
-		return helpers.ToFloat(left) * helpers.ToFloat(right), nil
-	case ast.LESS:
-		if err := p.checkNumberOperands(left, right); err != nil {
-			return nil, errors.NewRuntimeError(node.Operator, err.Error())
-		}
-		return helpers.ToFloat(left) < helpers.ToFloat(right), nil
-	case ast.LESS_EQUAL:
-		if err := p.checkNumberOperands(left, right); err != nil {
-			return nil, errors.NewRuntimeError(node.Operator, err.Error())
-		}
-		return helpers.ToFloat(left) <= helpers.ToFloat(right), nil
-	case ast.GREATER:
-		if err := p.checkNumberOperands(left, right); err != nil {
-			return nil, errors.NewRuntimeError(node.Operator, err.Error())
-		}
-		return helpers.ToFloat(left) > helpers.ToFloat(right), nil
-	case ast.GREATER_EQUAL:
-		if err := p.checkNumberOperands(left, right); err != nil {
-			return nil, errors.NewRuntimeError(node.Operator, err.Error())
-		}
-		return helpers.ToFloat(left) >= helpers.ToFloat(right), nil
+	result, err := binary(node.Operator, left, right)
+	if err == nil && !p.limits.allocate(result) {
+		return nil, p.limits.exceeded(node.Operator)
 	}
 
-	return nil, nil
+	return result, err
 }
 
 func (p *Interpreter) VisitLiteralExpr(node *ast.LiteralExpr) (any, error) {
@@ -366,11 +563,7 @@ func (p *Interpreter) VisitUnaryExpr(node *ast.UnaryExpr) (any, error) {
 	case ast.BANG:
 		return !helpers.IsTruthy(right), nil
 	case ast.MINUS:
-		if !helpers.IsFloat(right) {
-			return nil, errors.NewRuntimeError(node.Operator, "Operand must be a number.")
-		}
-
-		return -1 * helpers.ToFloat(right), nil
+		return negate(node.Operator, right)
 	}
 
 	return nil, nil
@@ -387,11 +580,15 @@ func (p *Interpreter) VisitArrayExpr(node *ast.ArrayExpr) (any, error) {
 		result = append(result, item)
 	}
 
-	return result, nil
+	array := callable.NewArray(result)
+	if !p.limits.allocate(array) {
+		return nil, p.limits.exceeded(node.Token)
+	}
+
+	return array, nil
 }
 
 func (p *Interpreter) VisitIndexExpr(node *ast.IndexExpr) (any, error) {
-	// Evaluate the object (could be an array or an object)
 	targetVal, err := node.Array.Accept(p)
 	if err != nil {
 		return nil, err
@@ -402,45 +599,47 @@ func (p *Interpreter) VisitIndexExpr(node *ast.IndexExpr) (any, error) {
 		return nil, err
 	}
 
-	// Handle Array Indexing
-	if arr, ok := targetVal.([]any); ok {
-		if !helpers.IsFloat(indexVal) {
-			return nil, errors.NewRuntimeError(node.Token, "Array index must be a number.")
-		}
+	return getIndex(targetVal, indexVal, node.Token)
+}
 
-		idx := int(indexVal.(float64))
-		if idx < 0 || idx >= len(arr) {
-			return nil, errors.NewRuntimeError(node.Token, fmt.Sprintf("Index out of bounds: %v of %v", idx, len(arr)))
-		}
+func (p *Interpreter) VisitSetIndexExpr(node *ast.SetIndexExpr) (any, error) {
+	targetVal, err := node.Array.Accept(p)
+	if err != nil {
+		return nil, err
+	}
 
-		return arr[idx], nil
+	indexVal, err := node.Index.Accept(p)
+	if err != nil {
+		return nil, err
 	}
 
-	// Handle Object Property Access
-	if obj, ok := targetVal.(map[string]any); ok {
-		key, ok := indexVal.(string)
-		if !ok {
-			return nil, errors.NewRuntimeError(node.Token, "Object keys must be strings.")
-		}
+	value, err := node.Value.Accept(p)
+	if err != nil {
+		return nil, err
+	}
 
-		value, exists := obj[key]
-		if !exists {
-			return nil, errors.NewRuntimeError(node.Token, fmt.Sprintf("Undefined property '%s'.", key))
-		}
+	if !p.limits.allocateKey(targetVal, indexVal) {
+		return nil, p.limits.exceeded(node.Token)
+	}
 
-		return value, nil
+	if err := setIndex(targetVal, indexVal, value, node.Token); err != nil {
+		return nil, err
 	}
 
-	return nil, errors.NewRuntimeError(node.Token, "Indexing is only supported on arrays and objects.")
+	return value, nil
 }
 
-func (p *Interpreter) VisitSetIndexExpr(node *ast.SetIndexExpr) (any, error) {
-	targetVal, err := node.Array.Accept(p)
+func (p *Interpreter) VisitGetExpr(node *ast.GetExpr) (any, error) {
+	object, err := node.Object.Accept(p)
 	if err != nil {
 		return nil, err
 	}
 
-	indexVal, err := node.Index.Accept(p)
+	return getProperty(object, node.Name)
+}
+
+func (p *Interpreter) VisitSetExpr(node *ast.SetExpr) (any, error) {
+	object, err := node.Object.Accept(p)
 	if err != nil {
 		return nil, err
 	}
@@ -450,60 +649,37 @@ func (p *Interpreter) VisitSetIndexExpr(node *ast.SetIndexExpr) (any, error) {
 		return nil, err
 	}
 
-	switch target := targetVal.(type) {
-	case []any:
-		idx, ok := indexVal.(float64)
-		if !ok || int(idx) < 0 || int(idx) >= len(target) {
-			return nil, errors.NewRuntimeError(
-				node.Token,
-				fmt.Sprintf("Index out of bounds: %v of %v", idx, len(target)),
-			)
-		}
-		target[int(idx)] = value
-		return value, nil
-
-	case map[string]any:
-		key, ok := indexVal.(string)
-		if !ok {
-			return nil, errors.NewRuntimeError(
-				node.Token,
-				"Object properties must be accessed with string keys.",
-			)
-		}
-		target[key] = value
-		return value, nil
+	if !p.limits.allocateKey(object, node.Name.Lexeme) {
+		return nil, p.limits.exceeded(node.Name)
+	}
 
-	default:
-		return nil, errors.NewRuntimeError(
-			node.Token,
-			"Indexing is only supported on arrays and objects.",
-		)
+	if err := setProperty(object, node.Name, value); err != nil {
+		return nil, err
 	}
+
+	return value, nil
 }
 
 func (p *Interpreter) VisitObjectExpr(node *ast.ObjectExpr) (any, error) {
-	obj := make(map[string]any)
+	obj := callable.NewObject()
 
-	for key, value := range node.Pairs {
-		v, err := value.Accept(p)
+	for _, pair := range node.Pairs {
+		v, err := pair.Value.Accept(p)
 		if err != nil {
 			return nil, err
 		}
 
-		obj[key] = v
+		obj.Set(pair.Key.Lexeme, v)
 	}
 
-	return obj, nil
-}
-
-func (p *Interpreter) checkNumberOperands(left any, right any) error {
-	if helpers.IsFloat(left) && helpers.IsFloat(right) {
-		return nil
+	if !p.limits.allocate(obj) {
+		return nil, p.limits.exceeded(node.Token)
 	}
 
-	return fmt.Errorf("Operands must be numbers.")
+	return obj, nil
 }
 
-func (p *Interpreter) Resolve(expr ast.Expr, depth int) {
-	p.locals[expr] = depth
+// Resolve records that expr refers to the local in the given slot, depth scopes out.
+func (p *Interpreter) Resolve(expr ast.Expr, depth int, slot int) {
+	p.locals[expr] = localSlot{distance: depth, slot: slot}
 }
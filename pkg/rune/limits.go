@@ -0,0 +1,180 @@
+package rune
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/errors"
+)
+
+// Limits bound the resources a run may use, zero values mean no limit. Exceeding a
+// limit raises an error of its own kind, which scripts cannot catch.
+type Limits struct {
+	// MaxSteps bounds the number of loop iterations and function calls.
+	MaxSteps int
+	// Timeout bounds the wall-clock time of a run.
+	Timeout time.Duration
+	// MaxAllocation bounds the estimated number of bytes allocated for strings, arrays
+	// and objects. Strings count their length, arrays and objects valueSize per item,
+	// including the items added later by push, insert or a set of a new key.
+	MaxAllocation int
+}
+
+const (
+	// contextCheckInterval is the number of steps between two checks of the context.
+	contextCheckInterval = 1024
+	// valueSize is the estimated size of an item of an array or object.
+	valueSize = 16
+)
+
+// limiter enforces the limits of a run, it is shared by the interpreter and the VM.
+// Once a limit is exceeded every following step fails too, so finally blocks cannot
+// keep the run going.
+type limiter struct {
+	limits    Limits
+	ctx       context.Context
+	steps     int
+	allocated int
+	// kind and message describe the exceeded limit, kind is empty until then.
+	kind    string
+	message string
+}
+
+func newLimiter(limits Limits) *limiter {
+	return &limiter{limits: limits, ctx: context.Background()}
+}
+
+// start resets the counters for a run that is cancelled with ctx. The returned
+// function releases the timer of the timeout.
+func (l *limiter) start(ctx context.Context) context.CancelFunc {
+	l.steps = 0
+	l.allocated = 0
+	l.kind = ""
+
+	cancel := func() {}
+	if l.limits.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, l.limits.Timeout)
+	}
+
+	l.ctx = ctx
+
+	return cancel
+}
+
+// step counts a loop iteration or a call. It returns false once a limit is exceeded,
+// the error is then returned by exceeded.
+func (l *limiter) step() bool {
+	if l.kind != "" {
+		return false
+	}
+
+	l.steps++
+
+	if l.limits.MaxSteps > 0 && l.steps > l.limits.MaxSteps {
+		l.fail(errors.KindStepLimit, fmt.Sprintf("Step limit of %d exceeded.", l.limits.MaxSteps))
+		return false
+	}
+
+	if l.steps%contextCheckInterval == 0 {
+		return l.checkContext()
+	}
+
+	return true
+}
+
+func (l *limiter) checkContext() bool {
+	switch l.ctx.Err() {
+	case nil:
+		return true
+	case context.DeadlineExceeded:
+		if l.limits.Timeout > 0 {
+			l.fail(errors.KindTimeout, fmt.Sprintf("Execution timed out after %s.", l.limits.Timeout))
+		} else {
+			l.fail(errors.KindTimeout, "Execution timed out.")
+		}
+	default:
+		l.fail(errors.KindCancelled, "Execution cancelled.")
+	}
+
+	return false
+}
+
+// allocate counts the size of a string, array or object that was just created. It
+// returns false once a limit is exceeded, the error is then returned by exceeded.
+func (l *limiter) allocate(value any) bool {
+	if l.kind != "" {
+		return false
+	}
+
+	switch v := value.(type) {
+	case string:
+		return l.grow(len(v))
+	case *callable.Array:
+		return l.grow(v.Len() * valueSize)
+	case *callable.Object:
+		return l.grow(v.Len() * valueSize)
+	}
+
+	return true
+}
+
+// allocateKey counts the item a set adds to an object, when the key is not in it yet.
+// It must be called before the set. Fields of instances are not counted.
+func (l *limiter) allocateKey(target any, key any) bool {
+	if l.kind != "" {
+		return false
+	}
+
+	obj, ok := target.(*callable.Object)
+	name, isString := key.(string)
+	if !ok || !isString {
+		return true
+	}
+
+	if _, exists := obj.Get(name); exists {
+		return true
+	}
+
+	return l.grow(valueSize)
+}
+
+// grow counts size more bytes allocated.
+func (l *limiter) grow(size int) bool {
+	l.allocated += size
+
+	if l.limits.MaxAllocation > 0 && l.allocated > l.limits.MaxAllocation {
+		l.fail(errors.KindAllocationLimit, fmt.Sprintf("Allocation limit of %d bytes exceeded.", l.limits.MaxAllocation))
+		return false
+	}
+
+	return true
+}
+
+// allocateResult counts what a call to a native function allocated: its result, and the
+// items push and insert add to an array in place. Results of script functions were
+// counted when they were created.
+func (l *limiter) allocateResult(fn callable.Callable, args []any, result any) bool {
+	switch fn.(type) {
+	case *callable.FunctionCallable, *callable.ClassCallable, *closure, *boundMethod, *vmClass:
+		return true
+	case *callable.PushCallable:
+		return l.grow((len(args) - 1) * valueSize)
+	case *callable.InsertCallable:
+		return l.grow(valueSize)
+	}
+
+	return l.allocate(result)
+}
+
+func (l *limiter) fail(kind string, message string) {
+	l.kind = kind
+	l.message = message
+}
+
+// exceeded returns the error of the exceeded limit, raised at token.
+func (l *limiter) exceeded(token ast.Token) error {
+	return errors.NewLimitError(token, l.kind, l.message)
+}
@@ -0,0 +1,342 @@
+package rune
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/errors"
+	"rune/pkg/helpers"
+)
+
+// Rules of the linter, a warning names the rule that found it.
+const (
+	RuleUnusedVariable       = "unused-variable"
+	RuleUnusedParameter      = "unused-parameter"
+	RuleShadow               = "shadow"
+	RuleUnreachable          = "unreachable"
+	RuleUndeclaredAssignment = "undeclared-assignment"
+	RuleConstantCondition    = "constant-condition"
+	RuleNativeArity          = "native-arity"
+)
+
+// LintRules holds every rule of the linter.
+var LintRules = []string{
+	RuleUnusedVariable,
+	RuleUnusedParameter,
+	RuleShadow,
+	RuleUnreachable,
+	RuleUndeclaredAssignment,
+	RuleConstantCondition,
+	RuleNativeArity,
+}
+
+// ignoreDirective starts a comment that suppresses warnings, e.g.
+// "// rune:ignore unused-variable, shadow". Without rules it suppresses every warning.
+// A trailing comment applies to its own line, any other to the line after it.
+const ignoreDirective = "// rune:ignore"
+
+// Warning is a likely mistake found by the linter in a program that compiles.
+type Warning struct {
+	Rule    string
+	Token   ast.Token
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("[line: %d] Warning: %s [%s]", w.Token.Line, w.Message, w.Rule)
+}
+
+// Lint looks for the warnings of the given rules in a program without running it. The
+// warnings are in the order of the source, those suppressed by a rune:ignore comment
+// are left out. A program that does not compile is returned as a CompileError.
+func Lint(source []byte, rules []string) ([]Warning, error) {
+	tokens, comments, scanErrors := ScanWithComments(source)
+	if len(scanErrors) > 0 {
+		return nil, errors.NewCompileError(scanErrors)
+	}
+
+	stmts, parseErrors := ParseStmts(tokens)
+	if len(parseErrors) > 0 {
+		return nil, errors.NewCompileError(parseErrors)
+	}
+
+	resolver := NewResolver(nil)
+	symbols := resolver.RecordSymbols()
+	resolver.lint = &linter{writes: make(map[*Symbol]int)}
+
+	if resolveErrors := resolver.ResolveStmts(stmts); len(resolveErrors) > 0 {
+		return nil, errors.NewCompileError(resolveErrors)
+	}
+
+	resolver.lint.checkSymbols(symbols)
+
+	ignored := ignoredLines(comments)
+
+	var warnings []Warning
+	for _, w := range resolver.lint.warnings {
+		if !slices.Contains(rules, w.Rule) {
+			continue
+		}
+
+		if names, ok := ignored[w.Token.Line]; ok && (len(names) == 0 || slices.Contains(names, w.Rule)) {
+			continue
+		}
+
+		warnings = append(warnings, w)
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Token.Offset < warnings[j].Token.Offset
+	})
+
+	return warnings, nil
+}
+
+// ignoredLines maps the lines with suppressed warnings to the rules suppressed on them,
+// an empty list suppresses every rule.
+func ignoredLines(comments []ast.Comment) map[int][]string {
+	ignored := make(map[int][]string)
+
+	for _, comment := range comments {
+		rest, ok := strings.CutPrefix(comment.Text, ignoreDirective)
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+
+		line := comment.Line
+		if !comment.Trailing {
+			line++
+		}
+
+		names := strings.FieldsFunc(rest, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+
+		if len(names) == 0 {
+			ignored[line] = []string{}
+		} else if rules, ok := ignored[line]; !ok || len(rules) > 0 {
+			ignored[line] = append(rules, names...)
+		}
+	}
+
+	return ignored
+}
+
+// linter collects the warnings of a resolver. The checks that depend on the globals of
+// the program wait until it is resolved, since functions can refer to globals declared
+// after them.
+type linter struct {
+	warnings []Warning
+	// assignments are the assignments to names that are not locals.
+	assignments []ast.Token
+	// calls are the calls to names that are not locals.
+	calls []*ast.CallExpr
+	// locals are the locals that shadow no other local, they may shadow a global.
+	locals []ast.Token
+	// writes counts the assignments to each local, references that do not read it.
+	writes map[*Symbol]int
+}
+
+func (l *linter) warn(rule string, token ast.Token, format string, args ...any) {
+	l.warnings = append(l.warnings, Warning{Rule: rule, Token: token, Message: fmt.Sprintf(format, args...)})
+}
+
+// unreachable warns about the first statement after one that always jumps away.
+func (l *linter) unreachable(stmts []ast.Stmt) {
+	for i, stmt := range stmts[:max(len(stmts)-1, 0)] {
+		var keyword ast.Token
+
+		switch stmt := stmt.(type) {
+		case *ast.ReturnStmt:
+			keyword = stmt.Keyword
+		case *ast.ThrowStmt:
+			keyword = stmt.Keyword
+		case *ast.BreakStmt:
+			keyword = stmt.Keyword
+		case *ast.ContinueStmt:
+			keyword = stmt.Keyword
+		default:
+			continue
+		}
+
+		next := ast.Token{Position: ast.StmtStart(stmts[i+1])}
+		l.warn(RuleUnreachable, next, "Unreachable code after '%s'.", keyword.Lexeme)
+
+		return
+	}
+}
+
+// condition warns about the condition of an if or a loop that does not depend on
+// anything. Loops on a literal true are left alone, they are how infinite loops are
+// written.
+func (l *linter) condition(condition ast.Expr, loop bool) {
+	if literal, ok := condition.(*ast.LiteralExpr); ok && loop && literal.Value == true {
+		return
+	}
+
+	if token, ok := constant(condition); ok {
+		l.warn(RuleConstantCondition, token, "Condition is always %s.", truthiness(condition))
+	}
+}
+
+// checkSymbols runs the checks that need the whole program resolved.
+func (l *linter) checkSymbols(symbols *Symbols) {
+	globals := symbols.Globals()
+	natives := Builtins()
+
+	// A local that is only assigned is not used, its values are never read.
+	for _, symbol := range symbols.Declared {
+		if symbol.Global || len(symbol.References) > l.writes[symbol] || strings.HasPrefix(symbol.Name.Lexeme, "_") {
+			continue
+		}
+
+		switch {
+		case symbol.Kind == SymbolParameter:
+			l.warn(RuleUnusedParameter, symbol.Name, "Parameter '%s' is never used.", symbol.Name.Lexeme)
+		case symbol.Kind == SymbolCatchVariable:
+			l.warn(RuleUnusedVariable, symbol.Name, "Catch variable '%s' is never used, write 'catch { ... }' to leave it out.", symbol.Name.Lexeme)
+		case l.writes[symbol] > 0:
+			l.warn(RuleUnusedVariable, symbol.Name, "Local '%s' is assigned but never used.", symbol.Name.Lexeme)
+		default:
+			l.warn(RuleUnusedVariable, symbol.Name, "Local '%s' is never used.", symbol.Name.Lexeme)
+		}
+	}
+
+	// Only globals declared above a local are reported, a parameter is often named like
+	// a global of the script that calls the function.
+	for _, name := range l.locals {
+		if global, ok := globals[name.Lexeme]; ok && global.Name.Offset < name.Offset {
+			l.warn(RuleShadow, name, "'%s' shadows the global declared on line %d.", name.Lexeme, global.Name.Line)
+		}
+	}
+
+	for _, name := range l.assignments {
+		_, global := globals[name.Lexeme]
+		_, native := natives[name.Lexeme]
+
+		if !global && !native {
+			l.warn(RuleUndeclaredAssignment, name, "Assignment to undeclared variable '%s'.", name.Lexeme)
+		}
+	}
+
+	for _, call := range l.calls {
+		name := call.Callee.(*ast.VarExpr).Name
+		if _, ok := globals[name.Lexeme]; ok {
+			continue
+		}
+
+		native, ok := natives[name.Lexeme]
+		if !ok {
+			continue
+		}
+
+		least, most, ok := nativeArity(native)
+		if ok && (len(call.Args) < least || most >= 0 && len(call.Args) > most) {
+			l.warn(RuleNativeArity, name, "%s() expects %s but got %d.", name.Lexeme, describeArguments(least, most), len(call.Args))
+		}
+	}
+}
+
+// constant reports whether an expression is made of literals only, and returns its
+// first token.
+func constant(expr ast.Expr) (ast.Token, bool) {
+	switch expr := expr.(type) {
+	case *ast.LiteralExpr:
+		return expr.Token, true
+	case *ast.GroupingExpr:
+		return constant(expr.Expr)
+	case *ast.UnaryExpr:
+		_, ok := constant(expr.Right)
+		return expr.Operator, ok
+	case *ast.BinaryExpr:
+		left, ok := constant(expr.Left)
+		_, rightOk := constant(expr.Right)
+		return left, ok && rightOk
+	case *ast.LogicalExpr:
+		left, ok := constant(expr.Left)
+		_, rightOk := constant(expr.Right)
+		return left, ok && rightOk
+	}
+
+	return ast.Token{}, false
+}
+
+// truthiness names the truth value of a constant expression, evaluated the way the
+// interpreter would.
+func truthiness(expr ast.Expr) string {
+	value, err := EvaluateExpr(expr)
+	if err != nil {
+		return "an error"
+	}
+
+	if helpers.IsTruthy(value) {
+		return "true"
+	}
+
+	return "false"
+}
+
+// nativeArity returns the least and most arguments a native function takes, most is -1
+// when there is no limit. Natives taking a variable number of arguments tell it by the
+// signature of their doc, where optional parameters end with '?' and the last one may
+// end with '...'. ok is false when the arity is not known.
+func nativeArity(native callable.Callable) (least int, most int, ok bool) {
+	if arity := native.Arity(); arity >= 0 {
+		return arity, arity, true
+	}
+
+	documented, isDocumented := native.(callable.Documented)
+	if !isDocumented {
+		return 0, 0, false
+	}
+
+	signature, _, _ := strings.Cut(documented.Doc(), "\n")
+
+	open, close := strings.Index(signature, "("), strings.LastIndex(signature, ")")
+	if open < 0 || close < open {
+		return 0, 0, false
+	}
+
+	params := strings.TrimSpace(signature[open+1 : close])
+	if params == "" {
+		return 0, 0, true
+	}
+
+	for _, param := range strings.Split(params, ",") {
+		param = strings.TrimSpace(param)
+
+		switch {
+		case strings.HasSuffix(param, "..."):
+			return least, -1, true
+		case !strings.HasSuffix(param, "?"):
+			least++
+		}
+
+		most++
+	}
+
+	return least, most, true
+}
+
+func describeArguments(least int, most int) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+
+		return fmt.Sprintf("%d arguments", n)
+	}
+
+	switch {
+	case most < 0:
+		return "at least " + plural(least)
+	case least == most:
+		return plural(least)
+	default:
+		return fmt.Sprintf("%d to %s", least, plural(most))
+	}
+}
@@ -0,0 +1,34 @@
+package rune_test
+
+import (
+	"testing"
+
+	"rune/pkg/rune"
+)
+
+// lint returns the warnings of every rule in a program, which must compile.
+func lint(t *testing.T, source string) []rune.Warning {
+	t.Helper()
+
+	warnings, err := rune.Lint([]byte(source), rune.LintRules)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+
+	return warnings
+}
+
+func TestUnreachableColumn(t *testing.T) {
+	for _, stmt := range []string{`print "x";`, "var y = 2;", "if (y) print 1;", "fun g() {}", "class C {}"} {
+		source := "var y;\nfun f() {\n  return 1;\n  " + stmt + "\n}\n"
+
+		warnings := lint(t, source)
+		if len(warnings) == 0 || warnings[0].Rule != rune.RuleUnreachable {
+			t.Fatalf("%s: got warnings %v, want an unreachable statement", stmt, warnings)
+		}
+
+		if got, want := warnings[0].Token.Column, 3; got != want {
+			t.Errorf("%s: got the warning at column %d, want %d", stmt, got, want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package rune
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/environment"
+	"rune/pkg/errors"
+	"rune/pkg/sandbox"
+)
+
+const moduleExtension = ".rn"
+
+// module is a .rn file loaded by an import. Every module runs with its own globals,
+// so modules cannot clobber each other's variables.
+type module struct {
+	path        string
+	globals     *environment.Environment
+	exportNames []string
+	// exports are the values of the exported globals once the module has run. Imports
+	// copy them, later assignments in the module are not seen by importers.
+	exports map[string]any
+	// importer is the module whose import is running this one, it forms the import chain.
+	importer *module
+	loaded   bool
+}
+
+// runModuleFn runs the statements of a freshly loaded module with its globals.
+type runModuleFn func(mod *module, stmts []ast.Stmt) error
+
+// moduleLoader finds, caches and runs imported modules. It is shared by the interpreter
+// and the VM, which only differ in how the statements of a module are run.
+type moduleLoader struct {
+	modules map[string]*module
+	current *module
+	// root is the module of the script being run, nil if its path is not known.
+	root    *module
+	natives map[string]callable.Callable
+	// permissions must grant read access to modules outside the directory of the script.
+	permissions sandbox.Permissions
+	// binder receives the resolved locals of loaded modules, it is nil for the VM.
+	binder *Interpreter
+	run    runModuleFn
+}
+
+func newModuleLoader(natives map[string]callable.Callable, binder *Interpreter, run runModuleFn) *moduleLoader {
+	return &moduleLoader{
+		modules: make(map[string]*module),
+		natives: natives,
+		binder:  binder,
+		run:     run,
+	}
+}
+
+// SetScriptPath registers the file being run as the root module, relative imports
+// are resolved against its directory.
+func (p *Interpreter) SetScriptPath(path string) error {
+	return p.loader.setScriptPath(path, p.environment.Globals())
+}
+
+func (l *moduleLoader) setScriptPath(path string, globals *environment.Environment) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	l.current = &module{path: absPath, globals: globals}
+	l.root = l.current
+	l.modules[absPath] = l.current
+
+	return nil
+}
+
+func (p *Interpreter) VisitImportStmt(importStmt *ast.ImportStmt) error {
+	return p.loader.importModule(importStmt, p.environment.Define)
+}
+
+func (p *Interpreter) VisitExportStmt(exportStmt *ast.ExportStmt) error {
+	if err := exportStmt.Declaration.Accept(p); err != nil {
+		return err
+	}
+
+	p.loader.export(exportStmt.Name.Lexeme)
+
+	return nil
+}
+
+func (p *Interpreter) runModule(mod *module, stmts []ast.Stmt) error {
+	prevEnv := p.environment
+	p.environment = mod.globals
+
+	defer func() {
+		p.environment = prevEnv
+	}()
+
+	return p.EvaluateStmts(stmts)
+}
+
+// importModule loads the module of an import statement and defines the imported names.
+func (l *moduleLoader) importModule(importStmt *ast.ImportStmt, define func(name string, value any)) error {
+	mod, err := l.load(importStmt.Path)
+	if err != nil {
+		return err
+	}
+
+	if importStmt.Alias.Lexeme != "" {
+		// The namespace lists the exports in the order they are declared.
+		namespace := callable.NewObject()
+		for _, name := range mod.exportNames {
+			if value, ok := mod.exports[name]; ok {
+				namespace.Set(name, value)
+			}
+		}
+
+		define(importStmt.Alias.Lexeme, namespace)
+	}
+
+	for _, name := range importStmt.Names {
+		value, ok := mod.exports[name.Lexeme]
+		if !ok {
+			return errors.NewRuntimeError(
+				name,
+				fmt.Sprintf("Module '%s' does not export '%s'.", importStmt.Path.Literal, name.Lexeme),
+			)
+		}
+
+		define(name.Lexeme, value)
+	}
+
+	return nil
+}
+
+// importing reports whether an imported module is running rather than the script.
+func (l *moduleLoader) importing() bool {
+	return l.current != nil && l.current != l.root
+}
+
+// export marks a global of the module being run as exported.
+func (l *moduleLoader) export(name string) {
+	if l.current != nil {
+		l.current.exportNames = append(l.current.exportNames, name)
+	}
+}
+
+// load returns the module at the given import path, scanning, parsing, resolving
+// and running it the first time it is imported.
+func (l *moduleLoader) load(pathToken ast.Token) (*module, error) {
+	path := pathToken.Literal
+
+	if !strings.HasSuffix(path, moduleExtension) {
+		return nil, errors.NewRuntimeError(pathToken, fmt.Sprintf("Only %s files can be imported: '%s'.", moduleExtension, path))
+	}
+
+	if !filepath.IsAbs(path) {
+		dir := "."
+		if l.current != nil {
+			dir = filepath.Dir(l.current.path)
+		}
+
+		path = filepath.Join(dir, path)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errors.NewRuntimeError(pathToken, fmt.Sprintf("Cannot resolve module '%s'.", pathToken.Literal))
+	}
+
+	if !l.readable(absPath) {
+		return nil, errors.NewPermissionError(pathToken, fmt.Sprintf("Permission denied: import needs read access to '%s'.", pathToken.Literal))
+	}
+
+	if mod, ok := l.modules[absPath]; ok {
+		// A module that has not finished loading is still running one of its imports.
+		if !mod.loaded {
+			return nil, errors.NewRuntimeError(pathToken, fmt.Sprintf("Import cycle detected: %s", l.importChain(absPath)))
+		}
+
+		return mod, nil
+	}
+
+	source, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, errors.NewRuntimeError(pathToken, fmt.Sprintf("Cannot find module '%s'.", pathToken.Literal))
+	}
+
+	stmts, compileErrors := l.compile(source)
+	if len(compileErrors) > 0 {
+		return nil, errors.NewImportError(pathToken, pathToken.Literal, compileErrors)
+	}
+
+	mod := &module{
+		path:     absPath,
+		globals:  environment.NewEnvironment(nil),
+		exports:  make(map[string]any),
+		importer: l.current,
+	}
+
+	for name, native := range l.natives {
+		mod.globals.Define(name, native)
+	}
+
+	l.modules[absPath] = mod
+	l.current = mod
+
+	err = l.run(mod, stmts)
+	l.current = mod.importer
+
+	if err != nil {
+		delete(l.modules, absPath)
+		return nil, err
+	}
+
+	for _, name := range mod.exportNames {
+		value, err := mod.globals.Get(ast.NewToken(ast.IDENTIFIER, name, "", 0))
+		if err != nil {
+			return nil, err
+		}
+
+		mod.exports[name] = value
+	}
+
+	mod.loaded = true
+
+	return mod, nil
+}
+
+// readable reports whether the module at path can be imported. Modules in the directory
+// of the script, or in the working directory when its path is not known, need no
+// permission, others need read access.
+func (l *moduleLoader) readable(path string) bool {
+	dir := "."
+	if l.root != nil {
+		dir = filepath.Dir(l.root.path)
+	}
+
+	request := sandbox.Request{Kind: sandbox.Read, Target: path}
+	own := sandbox.Permissions{Read: []string{dir}}
+
+	return own.Allows(request) || l.permissions.Allows(request)
+}
+
+// importChain describes the cycle closed by importing path from the current module,
+// e.g. "a.rn -> b.rn -> a.rn".
+func (l *moduleLoader) importChain(path string) string {
+	chain := []string{displayPath(path)}
+
+	for mod := l.current; mod != nil; mod = mod.importer {
+		chain = append(chain, displayPath(mod.path))
+
+		if mod.path == path {
+			break
+		}
+	}
+
+	slices.Reverse(chain)
+
+	return strings.Join(chain, " -> ")
+}
+
+// displayPath shortens an absolute module path to be relative to the working directory.
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(wd, path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
+
+func (l *moduleLoader) compile(source []byte) ([]ast.Stmt, []error) {
+	tokens, scanErrors := Scan(source)
+	if len(scanErrors) > 0 {
+		return nil, scanErrors
+	}
+
+	stmts, parseErrors := ParseStmts(tokens)
+	if len(parseErrors) > 0 {
+		return nil, parseErrors
+	}
+
+	if resolveErrors := NewResolver(l.binder).ResolveStmts(stmts); len(resolveErrors) > 0 {
+		return nil, resolveErrors
+	}
+
+	return stmts, nil
+}
@@ -0,0 +1,197 @@
+package rune
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/errors"
+	"rune/pkg/helpers"
+)
+
+// The operations on values are shared by the interpreter and the VM, so both engines
+// compute the same results and report the same errors.
+
+// object is implemented by the class instances of both engines.
+type object interface {
+	Get(name string, token ast.Token) (any, error)
+	Set(name string, value any)
+}
+
+func binary(operator ast.Token, left any, right any) (any, error) {
+	switch operator.TokenType {
+	case ast.EQUAL_EQUAL:
+		return helpers.IsEqual(left, right), nil
+	case ast.BANG_EQUAL:
+		return !helpers.IsEqual(left, right), nil
+	case ast.PLUS:
+		if helpers.IsString(left) && helpers.IsString(right) {
+			return left.(string) + right.(string), nil
+		}
+
+		if helpers.IsFloat(left) && helpers.IsFloat(right) {
+			return left.(float64) + right.(float64), nil
+		}
+
+		return nil, errors.NewRuntimeError(operator, "Operands must be two numbers or two strings.")
+	}
+
+	if !helpers.IsFloat(left) || !helpers.IsFloat(right) {
+		return nil, errors.NewRuntimeError(operator, "Operands must be numbers.")
+	}
+
+	l, r := left.(float64), right.(float64)
+
+	switch operator.TokenType {
+	case ast.MINUS:
+		return l - r, nil
+	case ast.SLASH:
+		return l / r, nil
+	case ast.STAR:
+		return l * r, nil
+	case ast.LESS:
+		return l < r, nil
+	case ast.LESS_EQUAL:
+		return l <= r, nil
+	case ast.GREATER:
+		return l > r, nil
+	case ast.GREATER_EQUAL:
+		return l >= r, nil
+	}
+
+	return nil, nil
+}
+
+func negate(operator ast.Token, right any) (any, error) {
+	if !helpers.IsFloat(right) {
+		return nil, errors.NewRuntimeError(operator, "Operand must be a number.")
+	}
+
+	return -1 * helpers.ToFloat(right), nil
+}
+
+func getIndex(target any, index any, token ast.Token) (any, error) {
+	switch target := target.(type) {
+	case *callable.Array:
+		if !helpers.IsFloat(index) {
+			return nil, errors.NewRuntimeError(token, "Array index must be a number.")
+		}
+
+		idx := int(index.(float64))
+		if idx < 0 || idx >= target.Len() {
+			return nil, errors.NewRuntimeError(token, fmt.Sprintf("Index out of bounds: %v of %v", idx, target.Len()))
+		}
+
+		return target.Get(idx), nil
+
+	case *callable.Object:
+		key, ok := index.(string)
+		if !ok {
+			return nil, errors.NewRuntimeError(token, "Object keys must be strings.")
+		}
+
+		value, exists := target.Get(key)
+		if !exists {
+			return nil, errors.NewRuntimeError(token, fmt.Sprintf("Undefined property '%s'.", key))
+		}
+
+		return value, nil
+
+	case object:
+		key, ok := index.(string)
+		if !ok {
+			return nil, errors.NewRuntimeError(token, "Object keys must be strings.")
+		}
+
+		return target.Get(key, token)
+	}
+
+	return nil, errors.NewRuntimeError(token, "Indexing is only supported on arrays and objects.")
+}
+
+func setIndex(target any, index any, value any, token ast.Token) error {
+	switch target := target.(type) {
+	case *callable.Array:
+		idx, ok := index.(float64)
+		if !ok || int(idx) < 0 || int(idx) >= target.Len() {
+			return errors.NewRuntimeError(token, fmt.Sprintf("Index out of bounds: %v of %v", idx, target.Len()))
+		}
+
+		target.Set(int(idx), value)
+		return nil
+
+	case *callable.Object:
+		key, ok := index.(string)
+		if !ok {
+			return errors.NewRuntimeError(token, "Object properties must be accessed with string keys.")
+		}
+
+		target.Set(key, value)
+		return nil
+
+	case object:
+		key, ok := index.(string)
+		if !ok {
+			return errors.NewRuntimeError(token, "Object properties must be accessed with string keys.")
+		}
+
+		target.Set(key, value)
+		return nil
+	}
+
+	return errors.NewRuntimeError(token, "Indexing is only supported on arrays and objects.")
+}
+
+func getProperty(target any, name ast.Token) (any, error) {
+	switch target := target.(type) {
+	case *callable.Object:
+		value, exists := target.Get(name.Lexeme)
+		if !exists {
+			return nil, errors.NewRuntimeError(name, fmt.Sprintf("Undefined property '%s'.", name.Lexeme))
+		}
+
+		return value, nil
+
+	case object:
+		return target.Get(name.Lexeme, name)
+	}
+
+	return nil, errors.NewRuntimeError(name, "Only objects have properties.")
+}
+
+func setProperty(target any, name ast.Token, value any) error {
+	switch target := target.(type) {
+	case *callable.Object:
+		target.Set(name.Lexeme, value)
+		return nil
+
+	case object:
+		target.Set(name.Lexeme, value)
+		return nil
+	}
+
+	return errors.NewRuntimeError(name, "Only objects have fields.")
+}
+
+// caughtValue converts an error into the value bound by a catch clause. Thrown values are
+// caught as they are and runtime errors become an object with message, line and kind.
+// Control flow signals such as returns and loop jumps and exceeded limits are not
+// catchable.
+func caughtValue(err error) (any, bool) {
+	switch e := err.(type) {
+	case *errors.ThrownError:
+		return e.Value, true
+	case errors.RuntimeError:
+		if !e.Catchable() {
+			return nil, false
+		}
+
+		caught := callable.NewObject()
+		caught.Set("message", e.Message())
+		caught.Set("line", float64(e.Line()))
+		caught.Set("kind", e.Kind())
+
+		return caught, true
+	default:
+		return nil, false
+	}
+}
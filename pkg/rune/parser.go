@@ -25,25 +25,24 @@ func ParseExpr(tokens []ast.Token) (ast.Expr, error) {
 	return parser.expression()
 }
 
-func ParseStmts(tokens []ast.Token) ([]ast.Stmt, error) {
+// ParseStmts parses a whole program. After a syntax error the parser skips to the next
+// statement boundary and keeps going, so every error in the program is reported.
+func ParseStmts(tokens []ast.Token) ([]ast.Stmt, []error) {
 	var stmts []ast.Stmt
 
 	parser := Parser{
 		tokens:  tokens,
+		errors:  []error{},
 		current: 0,
 	}
 
 	for !parser.isAtEnd() {
-		stmt, err := parser.declaration()
-
-		if err != nil {
-			return nil, err
+		if stmt := parser.declaration(); stmt != nil {
+			stmts = append(stmts, stmt)
 		}
-
-		stmts = append(stmts, stmt)
 	}
 
-	return stmts, nil
+	return stmts, parser.errors
 }
 
 func (s *Parser) statement() (ast.Stmt, error) {
@@ -56,20 +55,54 @@ func (s *Parser) statement() (ast.Stmt, error) {
 	}
 
 	if s.match(ast.WHILE) {
-		return s.whileStatement()
+		return s.whileStatement(ast.Token{})
+	}
+
+	if s.match(ast.BREAK) {
+		keyword := s.previous()
+		label, err := s.loopJumpLabel("break")
+		if err != nil {
+			return nil, err
+		}
+
+		return ast.NewBreakStmt(keyword, label), nil
+	}
+
+	if s.match(ast.CONTINUE) {
+		keyword := s.previous()
+		label, err := s.loopJumpLabel("continue")
+		if err != nil {
+			return nil, err
+		}
+
+		return ast.NewContinueStmt(keyword, label), nil
+	}
+
+	if s.match(ast.TRY) {
+		return s.tryStatement()
+	}
+
+	if s.match(ast.THROW) {
+		return s.throwStatement()
+	}
+
+	if s.check(ast.IDENTIFIER) && s.peekNext().TokenType == ast.COLON {
+		return s.labeledStatement()
 	}
 
 	if s.match(ast.LEFT_BRACE) {
-		block, err := s.block()
+		brace := s.previous()
+
+		block, end, err := s.block()
 		if err != nil {
 			return nil, err
 		}
 
-		return ast.NewBlockStmt(block), nil
+		return ast.NewBlockStmt(brace, block, end), nil
 	}
 
 	if s.match(ast.FOR) {
-		return s.forStatement()
+		return s.forStatement(ast.Token{})
 	}
 
 	if s.match(ast.IF) {
@@ -79,7 +112,142 @@ func (s *Parser) statement() (ast.Stmt, error) {
 	return s.expressionStatement()
 }
 
-func (s *Parser) forStatement() (ast.Stmt, error) {
+func (s *Parser) tryStatement() (ast.Stmt, error) {
+	keyword := s.previous()
+
+	_, err := s.consume(ast.LEFT_BRACE, "Expect '{' after 'try'.")
+	if err != nil {
+		return nil, err
+	}
+
+	body, bodyEnd, err := s.block()
+	if err != nil {
+		return nil, err
+	}
+
+	var catchName, catchEnd, finallyEnd ast.Token
+	var catchBody, finallyBody []ast.Stmt
+
+	var catchKeyword, finallyKeyword ast.Token
+
+	if s.match(ast.CATCH) {
+		catchKeyword = s.previous()
+
+		if s.match(ast.LEFT_PAREN) {
+			catchName, err = s.consume(ast.IDENTIFIER, "Expect variable name after '('.")
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = s.consume(ast.RIGHT_PAREN, "Expect ')' after catch variable.")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = s.consume(ast.LEFT_BRACE, "Expect '{' before catch body.")
+		if err != nil {
+			return nil, err
+		}
+
+		catchBody, catchEnd, err = s.block()
+		if err != nil {
+			return nil, err
+		}
+
+		// An empty catch clause still catches, so it must not be confused with a missing one.
+		if catchBody == nil {
+			catchBody = []ast.Stmt{}
+		}
+	}
+
+	if s.match(ast.FINALLY) {
+		finallyKeyword = s.previous()
+
+		_, err = s.consume(ast.LEFT_BRACE, "Expect '{' before finally body.")
+		if err != nil {
+			return nil, err
+		}
+
+		finallyBody, finallyEnd, err = s.block()
+		if err != nil {
+			return nil, err
+		}
+
+		if finallyBody == nil {
+			finallyBody = []ast.Stmt{}
+		}
+	}
+
+	if catchBody == nil && finallyBody == nil {
+		return nil, s.error(s.peek(), "Expect 'catch' or 'finally' after try block.")
+	}
+
+	return &ast.TryStmt{
+		Keyword:        keyword,
+		Body:           body,
+		CatchKeyword:   catchKeyword,
+		CatchName:      catchName,
+		CatchBody:      catchBody,
+		FinallyKeyword: finallyKeyword,
+		FinallyBody:    finallyBody,
+		BodyEnd:        bodyEnd,
+		CatchEnd:       catchEnd,
+		FinallyEnd:     finallyEnd,
+	}, nil
+}
+
+func (s *Parser) throwStatement() (ast.Stmt, error) {
+	keyword := s.previous()
+
+	value, err := s.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.consume(ast.SEMICOLON, "Expect ';' after thrown value.")
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewThrowStmt(keyword, value), nil
+}
+
+// labeledStatement parses a loop prefixed with a label: outer: while (...) { ... }
+func (s *Parser) labeledStatement() (ast.Stmt, error) {
+	label := s.advance()
+	s.advance()
+
+	if s.match(ast.WHILE) {
+		return s.whileStatement(label)
+	}
+
+	if s.match(ast.FOR) {
+		return s.forStatement(label)
+	}
+
+	return nil, s.error(s.peek(), "Expect loop after label.")
+}
+
+// loopJumpLabel parses the optional label and the ';' that follow 'break' or 'continue'.
+func (s *Parser) loopJumpLabel(keyword string) (ast.Token, error) {
+	var label ast.Token
+
+	if s.match(ast.IDENTIFIER) {
+		label = s.previous()
+	}
+
+	_, err := s.consume(ast.SEMICOLON, fmt.Sprintf("Expect ';' after '%s'.", keyword))
+	if err != nil {
+		return ast.Token{}, err
+	}
+
+	return label, nil
+}
+
+func (s *Parser) forStatement(label ast.Token) (ast.Stmt, error) {
+	keyword := s.previous()
+
 	_, err := s.consume(ast.LEFT_PAREN, "Expect '(' after 'for'.")
 	if err != nil {
 		return nil, err
@@ -137,24 +305,22 @@ func (s *Parser) forStatement() (ast.Stmt, error) {
 		return nil, err
 	}
 
-	if increment != nil {
-		body = ast.NewBlockStmt([]ast.Stmt{body, ast.NewExprStmt(increment)})
-	}
-
 	if condition == nil {
-		condition = ast.NewLiteralExpr(ast.TRUE, true)
+		condition = ast.NewLiteralExpr(ast.TRUE, true, ast.Token{})
 	}
 
-	body = ast.NewWhileStmt(condition, body)
+	body = ast.NewWhileStmt(keyword, condition, body, increment, label)
 
 	if initializer != nil {
-		body = ast.NewBlockStmt([]ast.Stmt{initializer, body})
+		body = ast.NewBlockStmt(ast.Token{}, []ast.Stmt{initializer, body}, ast.Token{})
 	}
 
 	return body, nil
 }
 
-func (s *Parser) whileStatement() (ast.Stmt, error) {
+func (s *Parser) whileStatement(label ast.Token) (ast.Stmt, error) {
+	keyword := s.previous()
+
 	_, err := s.consume(ast.LEFT_PAREN, "Expect '(' after 'while'.")
 	if err != nil {
 		return nil, err
@@ -175,7 +341,7 @@ func (s *Parser) whileStatement() (ast.Stmt, error) {
 		return nil, err
 	}
 
-	return ast.NewWhileStmt(condition, body), nil
+	return ast.NewWhileStmt(keyword, condition, body, nil, label), nil
 }
 
 func (s *Parser) or() (ast.Expr, error) {
@@ -216,28 +382,27 @@ func (s *Parser) and() (ast.Expr, error) {
 	return expr, nil
 }
 
-func (s *Parser) block() ([]ast.Stmt, error) {
+// block parses the statements of a block after its '{', it also returns the closing '}'.
+func (s *Parser) block() ([]ast.Stmt, ast.Token, error) {
 	var stmts []ast.Stmt
 
 	for !s.check(ast.RIGHT_BRACE) && !s.isAtEnd() {
-		stmt, err := s.declaration()
-
-		if err != nil {
-			return nil, err
+		if stmt := s.declaration(); stmt != nil {
+			stmts = append(stmts, stmt)
 		}
-
-		stmts = append(stmts, stmt)
 	}
 
-	_, err := s.consume(ast.RIGHT_BRACE, "Expect '}' after block.")
+	end, err := s.consume(ast.RIGHT_BRACE, "Expect '}' after block.")
 	if err != nil {
-		return nil, err
+		return nil, ast.Token{}, err
 	}
 
-	return stmts, nil
+	return stmts, end, nil
 }
 
 func (s *Parser) ifStatement() (ast.Stmt, error) {
+	keyword := s.previous()
+
 	_, err := s.consume(ast.LEFT_PAREN, "Expect '(' after 'if'.")
 	if err != nil {
 		return nil, err
@@ -259,8 +424,10 @@ func (s *Parser) ifStatement() (ast.Stmt, error) {
 	}
 
 	var el ast.Stmt
+	var elseKeyword ast.Token
 
 	if s.match(ast.ELSE) {
+		elseKeyword = s.previous()
 		el, err = s.statement()
 
 		if err != nil {
@@ -268,7 +435,7 @@ func (s *Parser) ifStatement() (ast.Stmt, error) {
 		}
 	}
 
-	return ast.NewIfStmt(condition, then, el), nil
+	return ast.NewIfStmt(keyword, condition, then, el, elseKeyword), nil
 }
 
 func (s *Parser) expressionStatement() (ast.Stmt, error) {
@@ -285,8 +452,61 @@ func (s *Parser) expressionStatement() (ast.Stmt, error) {
 	return ast.NewExprStmt(expr), nil
 }
 
-func (s *Parser) declaration() (ast.Stmt, error) {
-	if s.match(ast.FUN) {
+// declaration parses a declaration or statement. On a syntax error it records the error,
+// synchronizes and returns nil.
+func (s *Parser) declaration() ast.Stmt {
+	stmt, err := s.declarationStmt()
+	if err != nil {
+		s.errors = append(s.errors, err)
+		s.synchronize()
+
+		return nil
+	}
+
+	return stmt
+}
+
+// synchronize discards tokens until it reaches what is likely the start of the next statement.
+func (s *Parser) synchronize() {
+	s.advance()
+
+	for !s.isAtEnd() {
+		if s.previous().TokenType == ast.SEMICOLON {
+			return
+		}
+
+		switch s.peek().TokenType {
+		case ast.CLASS, ast.FUN, ast.VAR, ast.FOR, ast.IF, ast.WHILE, ast.PRINT, ast.RETURN,
+			ast.BREAK, ast.CONTINUE, ast.TRY, ast.THROW, ast.IMPORT, ast.EXPORT:
+			return
+		}
+
+		s.advance()
+	}
+}
+
+func (s *Parser) declarationStmt() (ast.Stmt, error) {
+	if s.match(ast.IMPORT) {
+		return s.importDeclaration()
+	}
+
+	if s.match(ast.EXPORT) {
+		return s.exportDeclaration()
+	}
+
+	if s.match(ast.CLASS) {
+		return s.classDeclaration()
+	}
+
+	// 'test' is only a keyword when a description follows it, so it stays usable as a name.
+	if s.check(ast.IDENTIFIER) && s.peek().Lexeme == "test" && s.peekNext().TokenType == ast.STRING {
+		s.advance()
+		return s.testDeclaration()
+	}
+
+	// A 'fun' followed by '(' starts an anonymous function expression statement.
+	if s.check(ast.FUN) && s.peekNext().TokenType != ast.LEFT_PAREN {
+		s.advance()
 		return s.function("function")
 	}
 
@@ -297,26 +517,183 @@ func (s *Parser) declaration() (ast.Stmt, error) {
 	return s.statement()
 }
 
+func (s *Parser) importDeclaration() (ast.Stmt, error) {
+	keyword := s.previous()
+
+	var alias ast.Token
+	var names []ast.Token
+
+	if s.match(ast.LEFT_BRACE) {
+		for {
+			name, err := s.consume(ast.IDENTIFIER, "Expect imported name.")
+			if err != nil {
+				return nil, err
+			}
+
+			names = append(names, name)
+
+			if !s.match(ast.COMMA) {
+				break
+			}
+		}
+
+		_, err := s.consume(ast.RIGHT_BRACE, "Expect '}' after imported names.")
+		if err != nil {
+			return nil, err
+		}
+
+		if !s.matchContextual("from") {
+			return nil, s.error(s.peek(), "Expect 'from' after imported names.")
+		}
+	}
+
+	path, err := s.consume(ast.STRING, "Expect module path.")
+	if err != nil {
+		return nil, err
+	}
+
+	if names == nil && s.matchContextual("as") {
+		alias, err = s.consume(ast.IDENTIFIER, "Expect module name after 'as'.")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s.consume(ast.SEMICOLON, "Expect ';' after import.")
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewImportStmt(keyword, path, alias, names), nil
+}
+
+func (s *Parser) exportDeclaration() (ast.Stmt, error) {
+	keyword := s.previous()
+
+	var declaration ast.Stmt
+	var err error
+
+	switch {
+	case s.match(ast.CLASS):
+		declaration, err = s.classDeclaration()
+	case s.match(ast.FUN):
+		declaration, err = s.function("function")
+	case s.match(ast.VAR):
+		declaration, err = s.varDeclaration()
+	default:
+		return nil, s.error(s.peek(), "Expect declaration after 'export'.")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var name ast.Token
+
+	switch d := declaration.(type) {
+	case *ast.ClassStmt:
+		name = d.Name
+	case *ast.FunctionStmt:
+		name = d.Name
+	case *ast.VarStmt:
+		name = d.Name
+	}
+
+	return ast.NewExportStmt(keyword, name, declaration), nil
+}
+
+// testDeclaration parses a test block after the 'test' keyword: test "description" { ... }
+func (s *Parser) testDeclaration() (ast.Stmt, error) {
+	keyword := s.previous()
+	name := s.advance()
+
+	_, err := s.consume(ast.LEFT_BRACE, "Expect '{' after test description.")
+	if err != nil {
+		return nil, err
+	}
+
+	body, end, err := s.block()
+	if err != nil {
+		return nil, err
+	}
+
+	function := &ast.FunctionStmt{Name: s.anonymousName(keyword), Body: body, End: end}
+
+	return ast.NewTestStmt(keyword, name, function), nil
+}
+
+func (s *Parser) classDeclaration() (ast.Stmt, error) {
+	keyword := s.previous()
+
+	name, err := s.consume(ast.IDENTIFIER, "Expect class name.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.consume(ast.LEFT_BRACE, "Expect '{' before class body.")
+	if err != nil {
+		return nil, err
+	}
+
+	methods := []*ast.FunctionStmt{}
+
+	for !s.check(ast.RIGHT_BRACE) && !s.isAtEnd() {
+		method, err := s.function("method")
+		if err != nil {
+			return nil, err
+		}
+
+		methods = append(methods, method.(*ast.FunctionStmt))
+	}
+
+	end, err := s.consume(ast.RIGHT_BRACE, "Expect '}' after class body.")
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewClassStmt(keyword, name, methods, end), nil
+}
+
 func (s *Parser) function(kind string) (ast.Stmt, error) {
+	// Methods are declared without 'fun'.
+	var keyword ast.Token
+	if kind == "function" {
+		keyword = s.previous()
+	}
+
 	name, err := s.consume(ast.IDENTIFIER, fmt.Sprintf("Expect %s name.", kind))
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.consume(ast.LEFT_PAREN, fmt.Sprintf("Expect '(' after %s name.", kind))
+	open, err := s.consume(ast.LEFT_PAREN, fmt.Sprintf("Expect '(' after %s name.", kind))
+	if err != nil {
+		return nil, err
+	}
+
+	parameters, err := s.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	close := s.previous()
+
+	body, end, err := s.functionBody()
 	if err != nil {
 		return nil, err
 	}
 
+	return ast.NewFunctionStmt(keyword, name, open, parameters, close, body, end), nil
+}
+
+// parameters parses a parameter list up to and including the closing ')'.
+func (s *Parser) parameters() ([]ast.Token, error) {
 	parameters := []ast.Token{}
 
 	if !s.check(ast.RIGHT_PAREN) {
 		for true {
 			if len(parameters) >= callable.MaxArity {
-				return nil, errors.NewRuntimeError(
-					s.peek(),
-					fmt.Sprintf("Error at '%s': Cannot have more than %d parameters.", s.peek().Lexeme, callable.MaxArity),
-				)
+				return nil, s.error(s.peek(), fmt.Sprintf("Cannot have more than %d parameters.", callable.MaxArity))
 			}
 
 			param, err := s.consume(ast.IDENTIFIER, "Expect parameter name.")
@@ -332,34 +709,125 @@ func (s *Parser) function(kind string) (ast.Stmt, error) {
 		}
 	}
 
-	_, err = s.consume(ast.RIGHT_PAREN, fmt.Sprintf(
-		"Error at '%s': Expect ')' after parameters.",
-		s.peek().Lexeme,
-	))
+	_, err := s.consume(ast.RIGHT_PAREN, "Expect ')' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+
+	return parameters, nil
+}
+
+func (s *Parser) functionBody() ([]ast.Stmt, ast.Token, error) {
+	_, err := s.consume(ast.LEFT_BRACE, "Expect '{' before function body.")
+	if err != nil {
+		return nil, ast.Token{}, err
+	}
+
+	return s.block()
+}
+
+// functionExpr parses an anonymous function after the 'fun' keyword: fun (a, b) { ... }
+func (s *Parser) functionExpr() (ast.Expr, error) {
+	keyword := s.previous()
+
+	open, err := s.consume(ast.LEFT_PAREN, "Expect '(' after 'fun'.")
+	if err != nil {
+		return nil, err
+	}
+
+	parameters, err := s.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	close := s.previous()
+
+	body, end, err := s.functionBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewFunctionExpr(s.anonymousName(keyword), open, parameters, close, body, end), nil
+}
+
+// arrowFunction parses the short form after its '(' has been consumed: (a, b) => a + b
+func (s *Parser) arrowFunction() (ast.Expr, error) {
+	open := s.previous()
+
+	parameters, err := s.parameters()
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.consume(ast.LEFT_BRACE, fmt.Sprintf(
-		"Error at '%s': Expect '{' before function body.",
-		s.peek().Lexeme,
-	))
+	close := s.previous()
+
+	arrow, err := s.consume(ast.ARROW, "Expect '=>' after parameters.")
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := s.block()
+	if s.match(ast.LEFT_BRACE) {
+		body, end, err := s.block()
+		if err != nil {
+			return nil, err
+		}
+
+		return ast.NewFunctionExpr(s.anonymousName(arrow), open, parameters, close, body, end), nil
+	}
+
+	value, err := s.expression()
 	if err != nil {
 		return nil, err
 	}
 
-	return ast.NewFunctionStmt(name, parameters, body), nil
+	body := []ast.Stmt{ast.NewReturnStmt(value, arrow)}
+
+	return ast.NewFunctionExpr(s.anonymousName(arrow), open, parameters, close, body, ast.Token{}), nil
+}
+
+// isArrowFunction looks ahead from just after a '(' to tell an arrow function
+// parameter list apart from a grouping expression.
+func (s *Parser) isArrowFunction() bool {
+	i := s.current
+
+	if s.tokens[i].TokenType != ast.RIGHT_PAREN {
+		for {
+			if s.tokens[i].TokenType != ast.IDENTIFIER {
+				return false
+			}
+
+			i++
+
+			if s.tokens[i].TokenType != ast.COMMA {
+				break
+			}
+
+			i++
+		}
+
+		if s.tokens[i].TokenType != ast.RIGHT_PAREN {
+			return false
+		}
+	}
+
+	return s.tokens[i+1].TokenType == ast.ARROW
+}
+
+// anonymousName makes the name token of an anonymous function, it has an empty lexeme
+// and the type and position of the token that introduced the function.
+func (s *Parser) anonymousName(token ast.Token) ast.Token {
+	name := ast.NewToken(token.TokenType, "", "", token.Line)
+	name.Position = token.Position
+
+	return name
 }
 
 func (s *Parser) varDeclaration() (ast.Stmt, error) {
+	keyword := s.previous()
+
 	name, err := s.consume(
 		ast.IDENTIFIER,
-		fmt.Sprintf("Error at '%s': Expect variable name.", s.peek().Lexeme),
+		"Expect variable name.",
 	)
 
 	if err != nil {
@@ -381,7 +849,7 @@ func (s *Parser) varDeclaration() (ast.Stmt, error) {
 		return nil, err
 	}
 
-	return ast.NewVarStmt(initializer, name), nil
+	return ast.NewVarStmt(keyword, initializer, name), nil
 }
 
 func (s *Parser) returnStmt() (ast.Stmt, error) {
@@ -407,21 +875,20 @@ func (s *Parser) returnStmt() (ast.Stmt, error) {
 }
 
 func (s *Parser) printStmt() (ast.Stmt, error) {
+	keyword := s.previous()
+
 	expr, err := s.expression()
 
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.consume(ast.SEMICOLON, fmt.Sprintf(
-		"Error at '%s': Expect ';' after value.",
-		s.peek().Lexeme,
-	))
+	_, err = s.consume(ast.SEMICOLON, "Expect ';' after value.")
 	if err != nil {
 		return nil, err
 	}
 
-	return ast.NewPrintStmt(expr), nil
+	return ast.NewPrintStmt(keyword, expr), nil
 }
 
 func (s *Parser) expression() (ast.Expr, error) {
@@ -436,6 +903,7 @@ func (s *Parser) assignment() (ast.Expr, error) {
 	}
 
 	if s.match(ast.EQUAL) {
+		equals := s.previous()
 		value, err := s.assignment()
 
 		if err != nil {
@@ -446,13 +914,17 @@ func (s *Parser) assignment() (ast.Expr, error) {
 			return ast.NewSetIndexExpr(arrayExpr.Token, arrayExpr.Array, arrayExpr.Index, value), nil
 		}
 
+		if getExpr, ok := expr.(*ast.GetExpr); ok {
+			return ast.NewSetExpr(getExpr.Object, getExpr.Name, value), nil
+		}
+
 		if s, ok := expr.(*ast.VarExpr); ok {
 			token := s.Name
 
 			return ast.NewAssignExpr(token, value), nil
 		}
 
-		return nil, errors.NewRuntimeError(s.peek(), "Error at '=': Invalid assignment target.")
+		return nil, s.error(equals, "Invalid assignment target.")
 	}
 
 	return expr, nil
@@ -540,6 +1012,13 @@ func (s *Parser) call() (ast.Expr, error) {
 			}
 
 			expr = ast.NewIndexExpr(expr, index, s.previous())
+		} else if s.match(ast.DOT) {
+			name, err := s.consume(ast.IDENTIFIER, "Expect property name after '.'.")
+			if err != nil {
+				return nil, err
+			}
+
+			expr = ast.NewGetExpr(expr, name)
 		} else {
 			break
 		}
@@ -549,6 +1028,7 @@ func (s *Parser) call() (ast.Expr, error) {
 }
 
 func (s *Parser) finishCall(expr ast.Expr) (ast.Expr, error) {
+	open := s.previous()
 	args := []ast.Expr{}
 
 	if !s.check(ast.RIGHT_PAREN) {
@@ -570,7 +1050,7 @@ func (s *Parser) finishCall(expr ast.Expr) (ast.Expr, error) {
 		return nil, err
 	}
 
-	return ast.NewCallExpr(s.previous(), expr, args), nil
+	return ast.NewCallExpr(s.previous(), open, expr, args), nil
 }
 
 func (s *Parser) term() (ast.Expr, error) {
@@ -617,7 +1097,8 @@ func (s *Parser) factor() (ast.Expr, error) {
 func (s *Parser) primary() (ast.Expr, error) {
 	// Parse object literal
 	if s.match(ast.LEFT_BRACE) {
-		pairs := make(map[string]ast.Expr)
+		brace := s.previous()
+		var pairs []ast.ObjectPair
 
 		for !s.check(ast.RIGHT_BRACE) && !s.isAtEnd() {
 			// Parse key (should be an identifier or string)
@@ -637,7 +1118,7 @@ func (s *Parser) primary() (ast.Expr, error) {
 				return nil, err
 			}
 
-			pairs[key.Lexeme] = value
+			pairs = append(pairs, ast.ObjectPair{Key: key, Value: value})
 
 			// Allow optional commas but not required before closing `}`
 			if !s.match(ast.COMMA) {
@@ -646,16 +1127,18 @@ func (s *Parser) primary() (ast.Expr, error) {
 		}
 
 		// Expect closing `}`
-		_, err := s.consume(ast.RIGHT_BRACE, "Expect '}' after object properties.")
+		end, err := s.consume(ast.RIGHT_BRACE, "Expect '}' after object properties.")
 		if err != nil {
 			return nil, err
 		}
 
-		return ast.NewObjectExpr(ast.OBJECT, pairs), nil
+		return ast.NewObjectExpr(ast.OBJECT, brace, pairs, end), nil
 	}
 
 	// Parse array literal
 	if s.match(ast.LEFT_BRACKET) {
+		bracket := s.previous()
+
 		var items []ast.Expr
 
 		if !s.check(ast.RIGHT_BRACKET) {
@@ -666,30 +1149,31 @@ func (s *Parser) primary() (ast.Expr, error) {
 				}
 				items = append(items, elem)
 
-				if !s.match(ast.COMMA) {
+				// A trailing comma is allowed before the closing `]`.
+				if !s.match(ast.COMMA) || s.check(ast.RIGHT_BRACKET) {
 					break
 				}
 			}
 		}
 
-		_, err := s.consume(ast.RIGHT_BRACKET, "Expect ']' after array elements.")
+		end, err := s.consume(ast.RIGHT_BRACKET, "Expect ']' after array elements.")
 		if err != nil {
 			return nil, err
 		}
 
-		return ast.NewArrayExpr(ast.ARRAY, items), nil
+		return ast.NewArrayExpr(ast.ARRAY, bracket, items, end), nil
 	}
 
 	if s.match(ast.TRUE) {
-		return ast.NewLiteralExpr(ast.TRUE, true), nil
+		return ast.NewLiteralExpr(ast.TRUE, true, s.previous()), nil
 	}
 
 	if s.match(ast.FALSE) {
-		return ast.NewLiteralExpr(ast.FALSE, false), nil
+		return ast.NewLiteralExpr(ast.FALSE, false, s.previous()), nil
 	}
 
 	if s.match(ast.NIL) {
-		return ast.NewLiteralExpr(ast.NIL, nil), nil
+		return ast.NewLiteralExpr(ast.NIL, nil, s.previous()), nil
 	}
 
 	if s.match(ast.NUMBER) {
@@ -697,16 +1181,25 @@ func (s *Parser) primary() (ast.Expr, error) {
 		value, err := strconv.ParseFloat(prev.Literal, 64)
 
 		if err != nil {
-			return nil, errors.NewRuntimeError(prev, "Invalid number.")
+			return nil, s.error(prev, "Invalid number.")
 		}
 
-		return ast.NewLiteralExpr(ast.NUMBER, value), nil
+		return ast.NewLiteralExpr(ast.NUMBER, value, prev), nil
 	}
 
 	if s.match(ast.STRING) {
 		prev := s.previous()
 
-		return ast.NewLiteralExpr(ast.STRING, prev.Literal), nil
+		return ast.NewLiteralExpr(ast.STRING, prev.Literal, prev), nil
+	}
+
+	if s.match(ast.THIS) {
+		return ast.NewThisExpr(s.previous()), nil
+	}
+
+	if s.check(ast.FUN) && s.peekNext().TokenType == ast.LEFT_PAREN {
+		s.advance()
+		return s.functionExpr()
 	}
 
 	if s.match(ast.IDENTIFIER) {
@@ -716,16 +1209,17 @@ func (s *Parser) primary() (ast.Expr, error) {
 	}
 
 	if s.match(ast.LEFT_PAREN) {
+		if s.isArrowFunction() {
+			return s.arrowFunction()
+		}
+
 		expr, err := s.expression()
 
 		if err != nil {
 			return nil, err
 		}
 
-		_, err = s.consume(ast.RIGHT_PAREN, fmt.Sprintf(
-			"Error at '%s': Expect ')' after expression.",
-			s.peek().Lexeme,
-		))
+		_, err = s.consume(ast.RIGHT_PAREN, "Expect ')' after expression.")
 		if err != nil {
 			return nil, err
 		}
@@ -733,12 +1227,7 @@ func (s *Parser) primary() (ast.Expr, error) {
 		return ast.NewGroupingExpr(expr), nil
 	}
 
-	current := s.peek()
-
-	return nil, errors.NewRuntimeError(
-		current,
-		fmt.Sprintf("Error at '%s': Expect expression.", current.Lexeme),
-	)
+	return nil, s.error(s.peek(), "Expect expression.")
 }
 
 func (s *Parser) match(tokenTypes ...ast.TokenType) bool {
@@ -750,6 +1239,16 @@ func (s *Parser) match(tokenTypes ...ast.TokenType) bool {
 	return false
 }
 
+// matchContextual consumes an identifier that acts as a keyword only in some places, like 'as' and 'from'.
+func (s *Parser) matchContextual(lexeme string) bool {
+	if s.check(ast.IDENTIFIER) && s.peek().Lexeme == lexeme {
+		s.advance()
+		return true
+	}
+
+	return false
+}
+
 func (s *Parser) check(tokenType ast.TokenType) bool {
 	if s.isAtEnd() {
 		return false
@@ -775,13 +1274,30 @@ func (s *Parser) consume(tokenType ast.TokenType, errMsg string) (ast.Token, err
 		return s.advance(), nil
 	}
 
-	return ast.Token{}, errors.NewRuntimeError(s.peek(), errMsg)
+	return ast.Token{}, s.error(s.peek(), errMsg)
+}
+
+// error makes a syntax error pointing at the given token.
+func (s *Parser) error(token ast.Token, message string) error {
+	if token.TokenType == ast.EOF {
+		return errors.NewRuntimeError(token, fmt.Sprintf("Error at end: %s", message))
+	}
+
+	return errors.NewRuntimeError(token, fmt.Sprintf("Error at '%s': %s", token.Lexeme, message))
 }
 
 func (s *Parser) peek() ast.Token {
 	return s.tokens[s.current]
 }
 
+func (s *Parser) peekNext() ast.Token {
+	if s.isAtEnd() {
+		return s.peek()
+	}
+
+	return s.tokens[s.current+1]
+}
+
 func (s *Parser) isAtEnd() bool {
 	return s.peek().TokenType == ast.EOF
 }
@@ -0,0 +1,37 @@
+package rune
+
+import (
+	"fmt"
+
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/errors"
+	"rune/pkg/sandbox"
+)
+
+// authorize checks that permissions grant everything a native function needs for a call
+// with args, it is shared by the interpreter and the VM.
+func authorize(permissions sandbox.Permissions, fn callable.Callable, args []any, token ast.Token) error {
+	guarded, ok := fn.(callable.Guarded)
+	if !ok {
+		return nil
+	}
+
+	for _, request := range guarded.Requires(args) {
+		if !permissions.Allows(request) {
+			return errors.NewPermissionError(token, fmt.Sprintf("Permission denied: %s() needs %s.", frameName(fn), request))
+		}
+	}
+
+	return nil
+}
+
+// callNative calls a native function. Those calling back the functions they are passed
+// do it with call, so that the callbacks are authorized as well.
+func callNative(fn callable.Callable, executeBlock callable.ExecuteBlockFn, call callable.CallFn, args []any, token ast.Token) (any, error) {
+	if caller, ok := fn.(callable.CallsBack); ok {
+		return caller.CallWith(call, args, token)
+	}
+
+	return fn.Call(executeBlock, args, token)
+}
@@ -3,15 +3,50 @@ package rune
 import (
 	"fmt"
 	"rune/pkg/ast"
+	"rune/pkg/callable"
 	"rune/pkg/errors"
+	"slices"
 )
 
-type Scope = map[string]bool
+// variable is a local declared in a scope, slot is its index in the environment of the
+// scope and defined is false while its initializer is being resolved. symbol is set
+// when symbols are recorded.
+type variable struct {
+	slot    int
+	defined bool
+	symbol  *Symbol
+}
+
+type Scope = map[string]*variable
+
+type functionType int
+
+const (
+	functionTypeNone functionType = iota
+	functionTypeFunction
+	functionTypeMethod
+	functionTypeInitializer
+)
+
+type classType int
+
+const (
+	classTypeNone classType = iota
+	classTypeClass
+)
 
 type Resolver struct {
-	interpreter *Interpreter
-	scopes      []Scope
-	isFunction  bool
+	interpreter     *Interpreter
+	scopes          []Scope
+	currentFunction functionType
+	currentClass    classType
+	// loops holds the labels of the enclosing loops, unlabeled loops are empty strings.
+	loops  []string
+	errors []error
+	// symbols records the declarations and references, it is nil unless requested.
+	symbols *Symbols
+	// lint collects the warnings of the linter, it is nil unless linting.
+	lint *linter
 }
 
 func NewResolver(interpreter *Interpreter) *Resolver {
@@ -20,6 +55,13 @@ func NewResolver(interpreter *Interpreter) *Resolver {
 	}
 }
 
+// RecordSymbols makes the resolver record the declarations of the programs it resolves
+// and what every identifier refers to in the returned symbols.
+func (p *Resolver) RecordSymbols() *Symbols {
+	p.symbols = &Symbols{}
+	return p.symbols
+}
+
 func (p *Resolver) VisitPrintStmt(printStmt *ast.PrintStmt) error {
 	_, err := p.resolveExpr(printStmt.Expr)
 
@@ -36,6 +78,8 @@ func (p *Resolver) VisitVarStmt(stmt *ast.VarStmt) error {
 		return err
 	}
 
+	p.record(stmt.Name, SymbolVariable, nil)
+
 	if stmt.Initializer != nil {
 		if _, err := p.resolveExpr(stmt.Initializer); err != nil {
 			return err
@@ -57,7 +101,7 @@ func (p *Resolver) has(name string) (bool, bool) {
 		return false, false
 	}
 
-	return true, v
+	return true, v.defined
 }
 
 func (p *Resolver) VisitVarExpr(expr *ast.VarExpr) (any, error) {
@@ -76,16 +120,23 @@ func (p *Resolver) VisitVarExpr(expr *ast.VarExpr) (any, error) {
 }
 
 func (p *Resolver) VisitBlockStmt(blockStmt *ast.BlockStmt) error {
+	return p.resolveBlock(blockStmt.Stmts)
+}
+
+func (p *Resolver) resolveBlock(stmts []ast.Stmt) error {
 	p.beginScope()
-	if err := p.ResolveStmts(blockStmt.Stmts); err != nil {
-		return err
-	}
-	p.endScope()
+	defer p.endScope()
+
+	p.resolveStmts(stmts)
 
 	return nil
 }
 
 func (p *Resolver) VisitIfStmt(blockStmt *ast.IfStmt) error {
+	if p.lint != nil {
+		p.lint.condition(blockStmt.Condition, false)
+	}
+
 	if _, err := p.resolveExpr(blockStmt.Condition); err != nil {
 		return err
 	}
@@ -103,12 +154,144 @@ func (p *Resolver) VisitIfStmt(blockStmt *ast.IfStmt) error {
 	return nil
 }
 
-func (p *Resolver) VisitWhileStmt(blockStmt *ast.WhileStmt) error {
-	if _, err := p.resolveExpr(blockStmt.Condition); err != nil {
+func (p *Resolver) VisitWhileStmt(whileStmt *ast.WhileStmt) error {
+	if whileStmt.Label.Lexeme != "" && slices.Contains(p.loops, whileStmt.Label.Lexeme) {
+		return errors.NewRuntimeError(
+			whileStmt.Label,
+			fmt.Sprintf("Error at '%s': Label with this name already declared in an enclosing loop.", whileStmt.Label.Lexeme),
+		)
+	}
+
+	if p.lint != nil {
+		p.lint.condition(whileStmt.Condition, true)
+	}
+
+	if _, err := p.resolveExpr(whileStmt.Condition); err != nil {
+		return err
+	}
+
+	if whileStmt.Increment != nil {
+		if _, err := p.resolveExpr(whileStmt.Increment); err != nil {
+			return err
+		}
+	}
+
+	p.loops = append(p.loops, whileStmt.Label.Lexeme)
+
+	defer func() {
+		p.loops = p.loops[:len(p.loops)-1]
+	}()
+
+	return p.resolveStmt(whileStmt.Body)
+}
+
+func (p *Resolver) VisitTryStmt(tryStmt *ast.TryStmt) error {
+	if err := p.resolveBlock(tryStmt.Body); err != nil {
 		return err
 	}
 
-	return p.resolveStmt(blockStmt.Body)
+	if tryStmt.CatchBody != nil {
+		if err := p.resolveCatch(tryStmt.CatchName, tryStmt.CatchBody); err != nil {
+			return err
+		}
+	}
+
+	if tryStmt.FinallyBody != nil {
+		return p.resolveBlock(tryStmt.FinallyBody)
+	}
+
+	return nil
+}
+
+func (p *Resolver) resolveCatch(name ast.Token, body []ast.Stmt) error {
+	p.beginScope()
+	defer p.endScope()
+
+	if name.Lexeme != "" {
+		if err := p.declare(name); err != nil {
+			return err
+		}
+
+		p.record(name, SymbolCatchVariable, nil)
+		p.define(name)
+	}
+
+	p.resolveStmts(body)
+
+	return nil
+}
+
+func (p *Resolver) VisitThrowStmt(throwStmt *ast.ThrowStmt) error {
+	_, err := p.resolveExpr(throwStmt.Value)
+	return err
+}
+
+func (p *Resolver) VisitImportStmt(importStmt *ast.ImportStmt) error {
+	if !p.isScopesEmpty() {
+		return errors.NewRuntimeError(
+			importStmt.Keyword,
+			fmt.Sprintf("Error at '%s': Can only import from top-level code.", importStmt.Keyword.Lexeme),
+		)
+	}
+
+	if importStmt.Alias.Lexeme != "" {
+		p.record(importStmt.Alias, SymbolImport, nil)
+	}
+
+	for _, name := range importStmt.Names {
+		p.record(name, SymbolImport, nil)
+	}
+
+	return nil
+}
+
+func (p *Resolver) VisitExportStmt(exportStmt *ast.ExportStmt) error {
+	if !p.isScopesEmpty() {
+		return errors.NewRuntimeError(
+			exportStmt.Keyword,
+			fmt.Sprintf("Error at '%s': Can only export from top-level code.", exportStmt.Keyword.Lexeme),
+		)
+	}
+
+	return p.resolveStmt(exportStmt.Declaration)
+}
+
+func (p *Resolver) VisitTestStmt(testStmt *ast.TestStmt) error {
+	if !p.isScopesEmpty() {
+		return errors.NewRuntimeError(
+			testStmt.Keyword,
+			fmt.Sprintf("Error at '%s': Can only declare tests in top-level code.", testStmt.Keyword.Lexeme),
+		)
+	}
+
+	return p.resolveFn(testStmt.Function, functionTypeFunction)
+}
+
+func (p *Resolver) VisitBreakStmt(breakStmt *ast.BreakStmt) error {
+	return p.resolveLoopJump(breakStmt.Keyword, breakStmt.Label)
+}
+
+func (p *Resolver) VisitContinueStmt(continueStmt *ast.ContinueStmt) error {
+	return p.resolveLoopJump(continueStmt.Keyword, continueStmt.Label)
+}
+
+// resolveLoopJump checks that a 'break' or 'continue' is inside a loop carrying its label, if any.
+func (p *Resolver) resolveLoopJump(keyword ast.Token, label ast.Token) error {
+	if len(p.loops) == 0 {
+		return errors.NewRuntimeError(
+			keyword,
+			fmt.Sprintf("Error at '%s': Cannot use '%s' outside of a loop.", keyword.Lexeme, keyword.Lexeme),
+		)
+	}
+
+	if label.Lexeme != "" && !slices.Contains(p.loops, label.Lexeme) {
+		return errors.NewRuntimeError(
+			label,
+			fmt.Sprintf("Error at '%s': Undefined label '%s'.", label.Lexeme, label.Lexeme),
+		)
+	}
+
+	return nil
 }
 
 func (p *Resolver) VisitFunctionStmt(fnStmt *ast.FunctionStmt) error {
@@ -116,12 +299,54 @@ func (p *Resolver) VisitFunctionStmt(fnStmt *ast.FunctionStmt) error {
 		return err
 	}
 
+	p.record(fnStmt.Name, SymbolFunction, fnStmt)
 	p.define(fnStmt.Name)
-	return p.resolveFn(fnStmt)
+	return p.resolveFn(fnStmt, functionTypeFunction)
+}
+
+func (p *Resolver) VisitClassStmt(classStmt *ast.ClassStmt) error {
+	enclosingClass := p.currentClass
+	p.currentClass = classTypeClass
+
+	defer func() {
+		p.currentClass = enclosingClass
+	}()
+
+	if err := p.declare(classStmt.Name); err != nil {
+		return err
+	}
+
+	var initializer *ast.FunctionStmt
+	for _, method := range classStmt.Methods {
+		if method.Name.Lexeme == callable.InitializerName {
+			initializer = method
+		}
+	}
+
+	p.record(classStmt.Name, SymbolClass, initializer)
+	p.define(classStmt.Name)
+
+	p.beginScope()
+	defer p.endScope()
+
+	p.peekScope()["this"] = &variable{slot: 0, defined: true}
+
+	for _, method := range classStmt.Methods {
+		fnType := functionTypeMethod
+		if method.Name.Lexeme == callable.InitializerName {
+			fnType = functionTypeInitializer
+		}
+
+		if err := p.resolveFn(method, fnType); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (p *Resolver) VisitReturnStmt(returnStmt *ast.ReturnStmt) error {
-	if !p.isFunction {
+	if p.currentFunction == functionTypeNone {
 		return errors.NewRuntimeError(
 			returnStmt.Keyword,
 			fmt.Sprintf("Error at '%s': Cannot return from top-level code.", returnStmt.Keyword.Lexeme),
@@ -129,6 +354,13 @@ func (p *Resolver) VisitReturnStmt(returnStmt *ast.ReturnStmt) error {
 	}
 
 	if returnStmt.Value != nil {
+		if p.currentFunction == functionTypeInitializer {
+			return errors.NewRuntimeError(
+				returnStmt.Keyword,
+				fmt.Sprintf("Error at '%s': Cannot return a value from an initializer.", returnStmt.Keyword.Lexeme),
+			)
+		}
+
 		_, err := p.resolveExpr(returnStmt.Value)
 		return err
 	}
@@ -144,6 +376,15 @@ func (p *Resolver) VisitAssignExpr(expr *ast.AssignExpr) (any, error) {
 	}
 
 	p.resolveLocal(expr, expr.Name)
+
+	if p.lint != nil {
+		if v := p.lookup(expr.Name.Lexeme, p.scopes); v == nil {
+			p.lint.assignments = append(p.lint.assignments, expr.Name)
+		} else if v.symbol != nil {
+			p.lint.writes[v.symbol]++
+		}
+	}
+
 	return nil, nil
 }
 
@@ -170,6 +411,27 @@ func (p *Resolver) VisitCallExpr(callExpr *ast.CallExpr) (any, error) {
 		}
 	}
 
+	if callee, ok := callExpr.Callee.(*ast.VarExpr); ok && p.lint != nil && p.lookup(callee.Name.Lexeme, p.scopes) == nil {
+		p.lint.calls = append(p.lint.calls, callExpr)
+	}
+
+	return nil, nil
+}
+
+func (p *Resolver) VisitFunctionExpr(expr *ast.FunctionExpr) (any, error) {
+	return nil, p.resolveFn(expr.Declaration, functionTypeFunction)
+}
+
+func (p *Resolver) VisitThisExpr(expr *ast.ThisExpr) (any, error) {
+	if p.currentClass == classTypeNone {
+		return nil, errors.NewRuntimeError(
+			expr.Keyword,
+			fmt.Sprintf("Error at '%s': Cannot use 'this' outside of a class.", expr.Keyword.Lexeme),
+		)
+	}
+
+	p.resolveLocal(expr, expr.Keyword)
+
 	return nil, nil
 }
 
@@ -233,35 +495,78 @@ func (p *Resolver) VisitSetIndexExpr(expr *ast.SetIndexExpr) (any, error) {
 	return nil, nil
 }
 
+func (p *Resolver) VisitGetExpr(expr *ast.GetExpr) (any, error) {
+	return p.resolveExpr(expr.Object)
+}
+
+func (p *Resolver) VisitSetExpr(expr *ast.SetExpr) (any, error) {
+	if _, err := p.resolveExpr(expr.Value); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.resolveExpr(expr.Object); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 func (p *Resolver) VisitObjectExpr(expr *ast.ObjectExpr) (any, error) {
 	for _, pair := range expr.Pairs {
-		if _, err := p.resolveExpr(pair); err != nil {
+		if _, err := p.resolveExpr(pair.Value); err != nil {
 			return nil, err
 		}
 	}
 	return nil, nil
 }
 
-func (p *Resolver) ResolveStmts(stmts []ast.Stmt) error {
+// ResolveStmts resolves a program and returns every error found. A statement with an
+// error is skipped and resolution continues with the next one.
+func (p *Resolver) ResolveStmts(stmts []ast.Stmt) []error {
+	p.errors = []error{}
+	p.resolveStmts(stmts)
+
+	if p.symbols != nil {
+		p.symbols.bindGlobals()
+	}
+
+	return p.errors
+}
+
+func (p *Resolver) resolveStmts(stmts []ast.Stmt) {
+	if p.lint != nil {
+		p.lint.unreachable(stmts)
+	}
+
 	for _, stmt := range stmts {
 		if err := p.resolveStmt(stmt); err != nil {
-			return err
+			p.errors = append(p.errors, err)
 		}
 	}
-
-	return nil
 }
 
 func (p *Resolver) resolveLocal(expr ast.Expr, name ast.Token) {
 	for i := len(p.scopes) - 1; i >= 0; i-- {
 		s := p.scopes[i]
-		if _, exists := s[name.Lexeme]; exists {
+		if v, exists := s[name.Lexeme]; exists {
 			depth := len(p.scopes) - 1 - i
 
-			p.interpreter.Resolve(expr, depth)
+			// A resolver without an interpreter only reports diagnostics.
+			if p.interpreter != nil {
+				p.interpreter.Resolve(expr, depth, v.slot)
+			}
+
+			if v.symbol != nil {
+				v.symbol.References = append(v.symbol.References, name)
+			}
+
 			return
 		}
 	}
+
+	if p.symbols != nil {
+		p.symbols.globalRefs = append(p.symbols.globalRefs, name)
+	}
 }
 
 func (p *Resolver) resolveStmt(stmt ast.Stmt) error {
@@ -282,23 +587,30 @@ func (p *Resolver) endScope() {
 	}
 }
 
-func (p *Resolver) resolveFn(fn *ast.FunctionStmt) error {
-	p.isFunction = true
+func (p *Resolver) resolveFn(fn *ast.FunctionStmt, fnType functionType) error {
+	enclosingFunction := p.currentFunction
+	enclosingLoops := p.loops
+	p.currentFunction = fnType
+	p.loops = nil
+
+	defer func() {
+		p.currentFunction = enclosingFunction
+		p.loops = enclosingLoops
+	}()
+
 	p.beginScope()
+	defer p.endScope()
 
 	for _, fnParam := range fn.Parameters {
 		if err := p.declare(fnParam); err != nil {
 			return err
 		}
 
+		p.record(fnParam, SymbolParameter, nil)
 		p.define(fnParam)
 	}
 
-	if err := p.ResolveStmts(fn.Body); err != nil {
-		return err
-	}
-
-	p.endScope()
+	p.resolveStmts(fn.Body)
 
 	return nil
 }
@@ -315,9 +627,52 @@ func (p *Resolver) declare(name ast.Token) error {
 		)
 	}
 
+	if p.lint != nil {
+		p.shadow(name)
+	}
+
+	// Locals are defined at runtime in the order they are declared, which gives their slots.
 	scope := p.peekScope()
 
-	scope[name.Lexeme] = false
+	scope[name.Lexeme] = &variable{slot: len(scope)}
+
+	return nil
+}
+
+// record adds a declaration to the recorded symbols, if any. Locals must be declared first.
+func (p *Resolver) record(name ast.Token, kind SymbolKind, function *ast.FunctionStmt) {
+	if p.symbols == nil {
+		return
+	}
+
+	symbol := p.symbols.declare(name, kind, p.isScopesEmpty(), function)
+
+	if !p.isScopesEmpty() {
+		p.peekScope()[name.Lexeme].symbol = symbol
+	}
+}
+
+// shadow warns when a local being declared has the name of a local of an enclosing
+// scope. Those that do not may still shadow a global, which is checked once every
+// global is known.
+func (p *Resolver) shadow(name ast.Token) {
+	outer := p.lookup(name.Lexeme, p.scopes[:len(p.scopes)-1])
+
+	if outer == nil || outer.symbol == nil {
+		p.lint.locals = append(p.lint.locals, name)
+		return
+	}
+
+	p.lint.warn(RuleShadow, name, "'%s' shadows the local declared on line %d.", name.Lexeme, outer.symbol.Name.Line)
+}
+
+// lookup returns the innermost local with a name in the scopes, or nil.
+func (p *Resolver) lookup(name string, scopes []Scope) *variable {
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if v, ok := scopes[i][name]; ok {
+			return v
+		}
+	}
 
 	return nil
 }
@@ -327,8 +682,7 @@ func (p *Resolver) define(name ast.Token) {
 		return
 	}
 
-	scope := p.peekScope()
-	scope[name.Lexeme] = true
+	p.peekScope()[name.Lexeme].defined = true
 }
 
 func (p *Resolver) isScopesEmpty() bool {
@@ -0,0 +1,281 @@
+package rune
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/environment"
+	"rune/pkg/errors"
+	"rune/pkg/sandbox"
+)
+
+// Engine selects how a Runtime runs programs.
+type Engine string
+
+const (
+	// EngineTree walks the syntax tree, it is the default.
+	EngineTree Engine = "tree"
+	// EngineVM compiles programs to bytecode and runs them on a stack VM.
+	EngineVM Engine = "vm"
+)
+
+// Options configure a Runtime. The zero value runs programs on the tree-walk
+// interpreter, printing to os.Stdout.
+type Options struct {
+	// Engine runs the programs, EngineTree if empty.
+	Engine Engine
+	// Stdout receives the output of print statements, os.Stdout if nil.
+	Stdout io.Writer
+	// Stderr receives the errors of Run and Call with the source line they point at,
+	// the way the command line prints them. Errors are not printed if it is nil.
+	Stderr io.Writer
+	// Path is the path of the script, relative imports are resolved against its
+	// directory. They are resolved against the working directory if it is empty.
+	Path string
+	// Globals are defined in the script before it runs. Go values are converted with
+	// callable.ToValue, so ints become numbers and slices arrays.
+	Globals map[string]any
+	// Functions are native functions available to the script and every module it
+	// imports, like the built-in ones.
+	Functions map[string]callable.Callable
+	// Limits bound every run and call, the context of the call is checked in loops
+	// and calls as well.
+	Limits Limits
+	// Permissions are the capabilities granted to native functions. Nothing is granted
+	// by default, so json, readFile and env raise a PermissionError.
+	Permissions sandbox.Permissions
+}
+
+// engine runs resolved programs, it is implemented by the Interpreter and the VM.
+type engine interface {
+	SetScriptPath(path string) error
+	SetOutput(w io.Writer)
+	EvaluateStmts(stmts []ast.Stmt) error
+	Globals() *environment.Environment
+	registerGlobalCallable(name string, value callable.Callable)
+	setLimiter(l *limiter)
+	SetPermissions(permissions sandbox.Permissions)
+	setTestSuite(suite *testSuite)
+	callFunction(fn any, args []any) (any, error)
+	arity(fn any) (int, bool)
+}
+
+// Runtime runs Rune programs inside a Go program. Globals persist between runs, so
+// a host can load a script once and then call its functions.
+type Runtime struct {
+	engine   engine
+	resolver *Resolver
+	stderr   io.Writer
+	limits   *limiter
+	// active counts the runs and calls in progress, a call made by a native function
+	// returns its error to the script, which may catch it.
+	active int
+}
+
+// NewRuntime returns a runtime configured by opts.
+func NewRuntime(opts Options) (*Runtime, error) {
+	r := &Runtime{stderr: opts.Stderr, limits: newLimiter(opts.Limits)}
+
+	switch opts.Engine {
+	case EngineTree, "":
+		interpreter := NewInterpreter()
+		r.engine = interpreter
+		r.resolver = NewResolver(interpreter)
+	case EngineVM:
+		// The VM compiles variables to slots itself, the resolver only reports errors.
+		r.engine = NewVM()
+		r.resolver = NewResolver(nil)
+	default:
+		return nil, fmt.Errorf("unknown engine '%s'", opts.Engine)
+	}
+
+	r.engine.setLimiter(r.limits)
+	r.engine.SetPermissions(opts.Permissions)
+
+	if opts.Stdout != nil {
+		r.engine.SetOutput(opts.Stdout)
+	}
+
+	if opts.Path != "" {
+		if err := r.engine.SetScriptPath(opts.Path); err != nil {
+			return nil, fmt.Errorf("resolving script path: %w", err)
+		}
+	}
+
+	for name, value := range opts.Globals {
+		if err := r.Define(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, fn := range opts.Functions {
+		r.Register(name, fn)
+	}
+
+	return r, nil
+}
+
+// Run runs a program once with a new runtime configured by opts.
+func Run(ctx context.Context, source []byte, opts Options) error {
+	r, err := NewRuntime(opts)
+	if err != nil {
+		return err
+	}
+
+	return r.Run(ctx, source)
+}
+
+// Run scans, parses, resolves and runs a program. Scan, parse and resolve errors are
+// returned together as an *errors.CompileError and nothing is run. Cancelling ctx
+// stops the program with an error of kind errors.KindCancelled.
+func (r *Runtime) Run(ctx context.Context, source []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.enter(ctx)()
+
+	stmts, err := r.compile(source)
+	if err != nil {
+		return r.report(err)
+	}
+
+	return r.report(r.engine.EvaluateStmts(stmts))
+}
+
+func (r *Runtime) compile(source []byte) ([]ast.Stmt, error) {
+	tokens, scanErrors := Scan(source)
+	if len(scanErrors) > 0 {
+		return nil, errors.NewCompileError(scanErrors)
+	}
+
+	stmts, parseErrors := ParseStmts(tokens)
+	if len(parseErrors) > 0 {
+		return nil, errors.NewCompileError(parseErrors)
+	}
+
+	if resolveErrors := r.resolver.ResolveStmts(stmts); len(resolveErrors) > 0 {
+		return nil, errors.NewCompileError(resolveErrors)
+	}
+
+	return stmts, nil
+}
+
+// Define defines a global of the script, replacing any previous value. The value is
+// converted with callable.ToValue, it fails for values scripts cannot use.
+func (r *Runtime) Define(name string, value any) error {
+	v, err := hostValue(value)
+	if err != nil {
+		return fmt.Errorf("cannot define %s: %w", name, err)
+	}
+
+	r.engine.Globals().Define(name, v)
+
+	return nil
+}
+
+// Register makes a native function available to the script and the modules it imports.
+func (r *Runtime) Register(name string, fn callable.Callable) {
+	r.engine.registerGlobalCallable(name, fn)
+}
+
+// RegisterFunc binds a Go function with callable.NewGoCallable and registers it.
+func (r *Runtime) RegisterFunc(name string, fn any) error {
+	native, err := callable.NewGoCallable(name, fn)
+	if err != nil {
+		return err
+	}
+
+	r.Register(name, native)
+
+	return nil
+}
+
+// Get returns the value of a global of the script.
+func (r *Runtime) Get(name string) (any, bool) {
+	return r.engine.Globals().Lookup(name)
+}
+
+// Call calls the global function name with the given arguments and returns its result.
+func (r *Runtime) Call(ctx context.Context, name string, args ...any) (any, error) {
+	fn, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("undefined function '%s'", name)
+	}
+
+	return r.CallValue(ctx, fn, args...)
+}
+
+// CallValue calls a function value, such as a function passed by the script to a native
+// function, and returns its result. The arguments are converted with callable.ToValue.
+func (r *Runtime) CallValue(ctx context.Context, fn any, args ...any) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	values := make([]any, len(args))
+
+	for i, arg := range args {
+		value, err := hostValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("cannot pass argument %d to %s: %w", i+1, Stringify(fn), err)
+		}
+
+		values[i] = value
+	}
+
+	arity, ok := r.engine.arity(fn)
+	if !ok {
+		return nil, fmt.Errorf("cannot call %s, it is not a function", Stringify(fn))
+	}
+
+	if arity != -1 && len(args) != arity {
+		return nil, fmt.Errorf("%s expects %d arguments but got %d", Stringify(fn), arity, len(args))
+	}
+
+	defer r.enter(ctx)()
+
+	result, err := r.engine.callFunction(fn, values)
+
+	return result, r.report(err)
+}
+
+// hostValue converts a Go value of the host to a Rune value. Instances of the classes of
+// scripts are values already, they are kept as they are.
+func hostValue(value any) (any, error) {
+	if _, ok := value.(object); ok {
+		return value, nil
+	}
+
+	return callable.ToValue(value)
+}
+
+// enter starts a run or call, the outermost one starts counting against the limits.
+// The returned function ends it.
+func (r *Runtime) enter(ctx context.Context) func() {
+	r.active++
+
+	if r.active > 1 {
+		return func() { r.active-- }
+	}
+
+	cancel := r.limits.start(ctx)
+
+	return func() {
+		cancel()
+		r.active--
+	}
+}
+
+// report prints an error of the outermost run or call to the configured stderr and
+// returns it.
+func (r *Runtime) report(err error) error {
+	if err != nil && r.stderr != nil && r.active == 1 {
+		fmt.Fprintln(r.stderr, errors.Annotate(err))
+	}
+
+	return err
+}
@@ -0,0 +1,256 @@
+package rune_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"rune/pkg/callable"
+	"rune/pkg/errors"
+	"rune/pkg/rune"
+)
+
+var engines = []rune.Engine{rune.EngineTree, rune.EngineVM}
+
+// newRuntime returns a runtime of the engine printing to the returned buffer.
+func newRuntime(t *testing.T, engine rune.Engine, opts rune.Options) (*rune.Runtime, *bytes.Buffer) {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	opts.Engine = engine
+	opts.Stdout = &stdout
+
+	r, err := rune.NewRuntime(opts)
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+
+	return r, &stdout
+}
+
+func TestCallbackPermissions(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(string(engine), func(t *testing.T) {
+			r, _ := newRuntime(t, engine, rune.Options{})
+
+			err := r.RegisterFunc("apply", func(f func(string) (any, error), s string) (any, error) { return f(s) })
+			if err != nil {
+				t.Fatalf("RegisterFunc: %v", err)
+			}
+
+			for _, source := range []string{`apply(env, "HOME");`, `apply(readFile, "/etc/hostname");`} {
+				err := r.Run(context.Background(), []byte(source))
+
+				runtimeErr, ok := err.(errors.RuntimeError)
+				if !ok || runtimeErr.Kind() != errors.KindPermission {
+					t.Errorf("%s: got error %v, want a permission error", source, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCallbackTrace(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(string(engine), func(t *testing.T) {
+			r, _ := newRuntime(t, engine, rune.Options{})
+
+			err := r.RegisterFunc("apply", func(f func(string) (any, error), s string) (any, error) { return f(s) })
+			if err != nil {
+				t.Fatalf("RegisterFunc: %v", err)
+			}
+
+			err = r.Run(context.Background(), []byte("fun fail(s) {\n  throw s;\n}\napply(fail, \"x\");\n"))
+			if err == nil {
+				t.Fatal("got no error")
+			}
+
+			if trace := errors.Annotate(err); !strings.Contains(trace, "at fail (line 2)") {
+				t.Errorf("trace does not show the callback:\n%s", trace)
+			}
+		})
+	}
+}
+
+func TestHostValues(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(string(engine), func(t *testing.T) {
+			r, stdout := newRuntime(t, engine, rune.Options{
+				Globals: map[string]any{"n": 2, "xs": []any{1.0, "a"}, "m": map[string]int{"b": 2, "a": 1}},
+			})
+
+			source := "print n + 1; print xs == xs; print xs; print m; fun add(a, b) { return a + b; }"
+			if err := r.Run(context.Background(), []byte(source)); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			if got, want := stdout.String(), "3\ntrue\n[1, \"a\"]\n{a: 1, b: 2}\n"; got != want {
+				t.Errorf("got output %q, want %q", got, want)
+			}
+
+			sum, err := r.Call(context.Background(), "add", 1, int8(2))
+			if err != nil || sum != 3.0 {
+				t.Errorf("add(1, 2) = %v, %v, want 3", sum, err)
+			}
+
+			if _, err := r.Call(context.Background(), "add", 1, make(chan int)); err == nil {
+				t.Error("calling with a channel succeeded")
+			}
+
+			if err := r.Define("ch", make(chan int)); err == nil {
+				t.Error("defining a channel succeeded")
+			}
+		})
+	}
+}
+
+func TestHostValuesUnsupported(t *testing.T) {
+	_, err := rune.NewRuntime(rune.Options{Globals: map[string]any{"ch": make(chan int)}})
+	if err == nil {
+		t.Error("NewRuntime with a channel global succeeded")
+	}
+}
+
+func TestCall(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(string(engine), func(t *testing.T) {
+			r, _ := newRuntime(t, engine, rune.Options{})
+
+			source := `
+var total = 0;
+fun add(a, b) { return a + b; }
+fun count(n) { total = total + n; return total; }
+class Counter { init(start) { this.n = start; } }
+`
+			if err := r.Run(context.Background(), []byte(source)); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			if got, err := r.Call(context.Background(), "add", "a", "b"); err != nil || got != "ab" {
+				t.Errorf("add = %v, %v, want ab", got, err)
+			}
+
+			// Globals persist between calls.
+			r.Call(context.Background(), "count", 2)
+			if got, _ := r.Call(context.Background(), "count", 3); got != 5.0 {
+				t.Errorf("count = %v, want 5", got)
+			}
+
+			if total, ok := r.Get("total"); !ok || total != 5.0 {
+				t.Errorf("total = %v, want 5", total)
+			}
+
+			counter, err := r.Call(context.Background(), "Counter", 1)
+			if err != nil || !strings.Contains(rune.Stringify(counter), "Counter instance") {
+				t.Errorf("Counter(1) = %v, %v, want an instance", counter, err)
+			}
+
+			if _, err := r.Call(context.Background(), "missing"); err == nil || err.Error() != "undefined function 'missing'" {
+				t.Errorf("got error %v, want an undefined function", err)
+			}
+
+			if _, err := r.Call(context.Background(), "add", 1); err == nil || !strings.Contains(err.Error(), "expects 2 arguments but got 1") {
+				t.Errorf("got error %v, want an arity mismatch", err)
+			}
+
+			if _, err := r.Call(context.Background(), "total"); err == nil || !strings.Contains(err.Error(), "it is not a function") {
+				t.Errorf("got error %v, want a call of a non-function", err)
+			}
+
+			if _, err := r.Call(context.Background(), "add", 1, "a"); err == nil {
+				t.Error("adding a number and a string succeeded")
+			}
+		})
+	}
+}
+
+func TestCallValue(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(string(engine), func(t *testing.T) {
+			r, stdout := newRuntime(t, engine, rune.Options{})
+
+			var handler any
+			r.Register("onEvent", callable.NewFuncCallable("onEvent", 1, func(args []any) (any, error) {
+				handler = args[0]
+				return nil, nil
+			}))
+
+			source := `onEvent(fun (name) { print "got " + name; return len(name); });`
+			if err := r.Run(context.Background(), []byte(source)); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			got, err := r.CallValue(context.Background(), handler, "click")
+			if err != nil || got != 5.0 {
+				t.Errorf("handler = %v, %v, want 5", got, err)
+			}
+
+			if stdout.String() != "got click\n" {
+				t.Errorf("got output %q", stdout.String())
+			}
+
+			if _, err := r.CallValue(context.Background(), 1.0); err == nil {
+				t.Error("calling a number succeeded")
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			if _, err := r.CallValue(ctx, handler, "click"); err != context.Canceled {
+				t.Errorf("got error %v, want the context error", err)
+			}
+		})
+	}
+}
+
+func TestOutput(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(string(engine), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			r, err := rune.NewRuntime(rune.Options{Engine: engine, Stdout: &stdout, Stderr: &stderr})
+			if err != nil {
+				t.Fatalf("NewRuntime: %v", err)
+			}
+
+			err = r.Run(context.Background(), []byte("print \"out\";\nfun fail() {\n  return nil.x;\n}\n"))
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			if stdout.String() != "out\n" || stderr.Len() != 0 {
+				t.Errorf("got output %q and errors %q", stdout.String(), stderr.String())
+			}
+
+			if _, err := r.Call(context.Background(), "fail"); err == nil {
+				t.Fatal("fail() succeeded")
+			}
+
+			want := "[line: 3] Only objects have properties.\n 3 |   return nil.x;\n   |              ^\n  at fail (line 3)\n"
+			if stderr.String() != want {
+				t.Errorf("got errors %q, want %q", stderr.String(), want)
+			}
+
+			stderr.Reset()
+
+			err = r.Run(context.Background(), []byte("print ;\nvar = 1;"))
+			if _, ok := err.(*errors.CompileError); !ok {
+				t.Fatalf("got error %v, want a compile error", err)
+			}
+
+			if strings.Count(stderr.String(), "Error at") != 2 {
+				t.Errorf("got errors %q, want both compile errors", stderr.String())
+			}
+		})
+	}
+}
+
+func TestNoStderr(t *testing.T) {
+	var stdout bytes.Buffer
+
+	err := rune.Run(context.Background(), []byte("print 1; print nil.x;"), rune.Options{Stdout: &stdout})
+	if err == nil || stdout.String() != "1\n" {
+		t.Errorf("got output %q and error %v", stdout.String(), err)
+	}
+}
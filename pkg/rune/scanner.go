@@ -1,26 +1,42 @@
 package rune
 
 import (
-	"errors"
 	"fmt"
 	"rune/pkg/ast"
+	"rune/pkg/errors"
 	"rune/pkg/helpers"
 	"strconv"
+	"strings"
 )
 
 type Scanner struct {
-	source string
-	tokens []ast.Token
-	errors []error
+	source   string
+	file     *ast.Source
+	tokens   []ast.Token
+	comments []ast.Comment
+	errors   []error
 
 	start   int
 	current int
 	line    int
+	// lineStart is the offset of the first character of the current line,
+	// column is the column of s.start and is kept for tokens spanning several lines.
+	lineStart int
+	column    int
 }
 
 func Scan(source []byte) ([]ast.Token, []error) {
+	tokens, _, errors := ScanWithComments(source)
+
+	return tokens, errors
+}
+
+// ScanWithComments scans a program like Scan and also returns its comments, in the order
+// they appear.
+func ScanWithComments(source []byte) ([]ast.Token, []ast.Comment, []error) {
 	scanner := &Scanner{
 		source:  string(source),
+		file:    ast.NewSource(string(source)),
 		tokens:  []ast.Token{},
 		errors:  []error{},
 		current: 0,
@@ -28,23 +44,37 @@ func Scan(source []byte) ([]ast.Token, []error) {
 		start:   0,
 	}
 
-	return scanner.scanTokens(), scanner.errors
+	return scanner.scanTokens(), scanner.comments, scanner.errors
 }
 
 func (s *Scanner) scanTokens() []ast.Token {
 	for !s.isAtEnd() {
 		s.start = s.current
+		s.column = s.start - s.lineStart + 1
 		s.scanToken()
 	}
 
-	s.tokens = append(
-		s.tokens,
-		ast.NewToken(ast.EOF, "", "", s.line),
-	)
+	s.start = s.current
+	s.column = s.start - s.lineStart + 1
+	s.tokens = append(s.tokens, s.newToken(ast.EOF, "", ""))
 
 	return s.tokens
 }
 
+// newToken makes a token starting at s.start.
+func (s *Scanner) newToken(tokenType ast.TokenType, lexeme string, literal string) ast.Token {
+	token := ast.NewToken(tokenType, lexeme, literal, s.line)
+	token.Column = s.column
+	token.Offset = s.start
+	token.Source = s.file
+
+	return token
+}
+
+func (s *Scanner) error(lexeme string, message string) {
+	s.errors = append(s.errors, errors.NewRuntimeError(s.newToken(ast.EOF, lexeme, ""), "Error: "+message))
+}
+
 func (s *Scanner) isAtEnd() bool {
 	return s.current >= len(s.source)
 }
@@ -94,7 +124,11 @@ func (s *Scanner) scanToken() {
 		break
 		// Operators
 	case '=':
-		s.addToken(helpers.If(s.match('='), ast.EQUAL_EQUAL, ast.EQUAL))
+		if s.match('>') {
+			s.addToken(ast.ARROW)
+		} else {
+			s.addToken(helpers.If(s.match('='), ast.EQUAL_EQUAL, ast.EQUAL))
+		}
 		break
 	case '!':
 		s.addToken(helpers.If(s.match('='), ast.BANG_EQUAL, ast.BANG))
@@ -111,14 +145,13 @@ func (s *Scanner) scanToken() {
 			for s.peek() != '\n' && !s.isAtEnd() {
 				s.advance()
 			}
+
+			s.comment()
 		} else {
 			s.addToken(ast.SLASH)
 		}
 	case '"':
-		if err := s.string(); err != nil {
-			s.errors = append(s.errors, fmt.Errorf("[line: %d] Error: %w", s.line, err))
-		}
-
+		s.string()
 		break
 		// Ignore whitespace.
 	case ' ':
@@ -127,6 +160,7 @@ func (s *Scanner) scanToken() {
 		break
 	case '\n':
 		s.line++
+		s.lineStart = s.current
 		break
 	default:
 		if helpers.IsAlpha(char) {
@@ -134,44 +168,50 @@ func (s *Scanner) scanToken() {
 		} else if helpers.IsDigit(char) {
 			s.number()
 		} else {
-			s.errors = append(s.errors, fmt.Errorf("[line: %d] Error: Unexpected character: %c", s.line, char))
+			s.error(string(char), fmt.Sprintf("Unexpected character: %c", char))
 		}
 	}
 }
 
+// comment keeps the comment that was just scanned as trivia.
+func (s *Scanner) comment() {
+	token := s.newToken(ast.EOF, "", "")
+	text := strings.TrimSuffix(s.source[s.start:s.current], "\r")
+	trailing := strings.TrimSpace(s.source[s.lineStart:s.start]) != ""
+
+	s.comments = append(s.comments, ast.Comment{Text: text, Trailing: trailing, Position: token.Position})
+}
+
 func (s *Scanner) addToken(tokenType ast.TokenType) {
 	lexeme := s.source[s.start:s.current]
 
-	s.tokens = append(
-		s.tokens,
-		ast.NewToken(tokenType, lexeme, "", s.line),
-	)
+	s.tokens = append(s.tokens, s.newToken(tokenType, lexeme, ""))
 }
 
 func (s *Scanner) addTokenWithLiteral(tokenType ast.TokenType, literal string) {
 	lexeme := s.source[s.start:s.current]
 
-	s.tokens = append(
-		s.tokens,
-		ast.NewToken(tokenType, lexeme, literal, s.line),
-	)
+	s.tokens = append(s.tokens, s.newToken(tokenType, lexeme, literal))
 }
 
 func (s *Scanner) currentChar() rune {
 	return rune(s.source[s.current])
 }
 
-func (s *Scanner) string() error {
+func (s *Scanner) string() {
 	for s.peek() != '"' && !s.isAtEnd() {
 		if s.peek() == '\n' {
 			s.line++
+			s.lineStart = s.current + 1
 		}
 
 		s.advance()
 	}
 
 	if s.isAtEnd() {
-		return errors.New("Unterminated string.")
+		// The span covers the opening quote, the rest of the file is not worth underlining.
+		s.error("\"", "Unterminated string.")
+		return
 	}
 
 	s.advance()
@@ -179,8 +219,6 @@ func (s *Scanner) string() error {
 	value := s.source[s.start+1 : s.current-1]
 
 	s.addTokenWithLiteral(ast.STRING, value)
-
-	return nil
 }
 
 func (s *Scanner) number() {
@@ -0,0 +1,95 @@
+package rune
+
+import (
+	"rune/pkg/ast"
+)
+
+// SymbolKind tells what declared a symbol.
+type SymbolKind int
+
+const (
+	SymbolVariable SymbolKind = iota
+	SymbolFunction
+	SymbolClass
+	SymbolParameter
+	SymbolImport
+	// SymbolCatchVariable is the variable a catch clause binds the caught value to.
+	SymbolCatchVariable
+)
+
+// Symbol is a declared name and the identifiers that refer to it.
+type Symbol struct {
+	Name ast.Token
+	Kind SymbolKind
+	// Global is true for top-level declarations, which are bound by name when used.
+	Global bool
+	// Function is the declaration of a function or the initializer of a class, if any.
+	Function   *ast.FunctionStmt
+	References []ast.Token
+}
+
+// Symbols records the declarations of a program and the identifiers referring to them,
+// for tools such as the language server. Set it on a resolver with RecordSymbols.
+type Symbols struct {
+	// Declared holds every symbol in the order it was declared.
+	Declared []*Symbol
+	// Unbound holds the identifiers that refer to no declaration of the program, such
+	// as native functions and misspelled names.
+	Unbound []ast.Token
+	// globalRefs are the identifiers that refer to no local, they are bound to the
+	// globals of the same name once the whole program is resolved.
+	globalRefs []ast.Token
+}
+
+// At returns the symbol declared or referred to by the identifier at a byte offset.
+func (s *Symbols) At(offset int) *Symbol {
+	for _, symbol := range s.Declared {
+		if symbol.Name.Offset == offset {
+			return symbol
+		}
+
+		for _, ref := range symbol.References {
+			if ref.Offset == offset {
+				return symbol
+			}
+		}
+	}
+
+	return nil
+}
+
+// Globals returns the global symbols, the last declaration of a name wins like it does
+// when the program runs.
+func (s *Symbols) Globals() map[string]*Symbol {
+	globals := make(map[string]*Symbol)
+
+	for _, symbol := range s.Declared {
+		if symbol.Global {
+			globals[symbol.Name.Lexeme] = symbol
+		}
+	}
+
+	return globals
+}
+
+func (s *Symbols) declare(name ast.Token, kind SymbolKind, global bool, function *ast.FunctionStmt) *Symbol {
+	symbol := &Symbol{Name: name, Kind: kind, Global: global, Function: function}
+	s.Declared = append(s.Declared, symbol)
+
+	return symbol
+}
+
+// bindGlobals binds the identifiers that referred to no local to the globals.
+func (s *Symbols) bindGlobals() {
+	globals := s.Globals()
+
+	for _, ref := range s.globalRefs {
+		if symbol, ok := globals[ref.Lexeme]; ok {
+			symbol.References = append(symbol.References, ref)
+		} else {
+			s.Unbound = append(s.Unbound, ref)
+		}
+	}
+
+	s.globalRefs = nil
+}
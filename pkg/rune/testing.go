@@ -0,0 +1,74 @@
+package rune
+
+import (
+	"context"
+
+	"rune/pkg/ast"
+)
+
+// TestResult is the outcome of a test block.
+type TestResult struct {
+	// Name is the description of the test.
+	Name string
+	// Line is the line of its 'test' keyword.
+	Line int
+	// Err is the error that failed the test, nil if it passed.
+	Err error
+}
+
+// testSuite collects the test blocks of a script while it runs, in source order.
+type testSuite struct {
+	tests []testCase
+}
+
+type testCase struct {
+	stmt *ast.TestStmt
+	fn   any
+}
+
+// add collects a test block, those of imported modules are skipped. A nil suite
+// collects nothing, so the engines can call it whether tests are run or not.
+func (s *testSuite) add(loader *moduleLoader, stmt *ast.TestStmt, fn any) {
+	if s == nil || loader.importing() {
+		return
+	}
+
+	s.tests = append(s.tests, testCase{stmt: stmt, fn: fn})
+}
+
+// RunTests runs a program, then each of its test blocks in order. Tests are
+// independent: every one gets its own limits and a failing one does not stop the
+// others. An error of the program itself is returned like Run does, failing tests are
+// reported in the results only.
+func (r *Runtime) RunTests(ctx context.Context, source []byte) ([]TestResult, error) {
+	suite := &testSuite{}
+	r.engine.setTestSuite(suite)
+	defer r.engine.setTestSuite(nil)
+
+	if err := r.Run(ctx, source); err != nil {
+		return nil, err
+	}
+
+	results := make([]TestResult, 0, len(suite.tests))
+	for _, test := range suite.tests {
+		results = append(results, TestResult{
+			Name: test.stmt.Name.Literal,
+			Line: test.stmt.Keyword.Line,
+			Err:  r.runTest(ctx, test.fn),
+		})
+	}
+
+	return results, nil
+}
+
+func (r *Runtime) runTest(ctx context.Context, fn any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.enter(ctx)()
+
+	_, err := r.engine.callFunction(fn, nil)
+
+	return err
+}
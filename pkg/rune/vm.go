@@ -0,0 +1,727 @@
+package rune
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/environment"
+	"rune/pkg/errors"
+	"rune/pkg/helpers"
+	"rune/pkg/sandbox"
+)
+
+// callFrame is an active call of a closure, its locals start at base on the stack.
+type callFrame struct {
+	closure *closure
+	ip      int
+	base    int
+	// name is shown in stack traces, constructors are shown with the name of their class.
+	name   string
+	isCall bool
+	// fromHost marks a call made by the host, its trace ends with it.
+	fromHost bool
+}
+
+// handler is an active try region, an error unwinds the stack to sp and jumps to target.
+type handler struct {
+	frame  int
+	sp     int
+	target int
+}
+
+// VM runs scripts compiled to bytecode. It is an alternative to the Interpreter that
+// produces the same output and errors.
+type VM struct {
+	stack        []any
+	frames       []callFrame
+	handlers     []handler
+	openUpvalues *upvalue
+	depth        int
+	maxRecursion int
+	globals      *environment.Environment
+	natives      map[string]callable.Callable
+	loader       *moduleLoader
+	// out receives the output of print statements.
+	out    io.Writer
+	limits *limiter
+	// permissions are the capabilities granted to native functions, none by default.
+	permissions sandbox.Permissions
+	// tests collects the test blocks of the script, it is nil unless they are run.
+	tests *testSuite
+}
+
+func NewVM() *VM {
+	vm := &VM{
+		out:          os.Stdout,
+		limits:       newLimiter(Limits{}),
+		globals:      environment.NewEnvironment(nil),
+		natives:      make(map[string]callable.Callable),
+		maxRecursion: maxRecursionDepth,
+	}
+
+	vm.loader = newModuleLoader(vm.natives, nil, vm.runModule)
+
+	// Global functions.
+	for name, fn := range Builtins() {
+		vm.registerGlobalCallable(name, fn)
+	}
+
+	return vm
+}
+
+func (vm *VM) registerGlobalCallable(name string, value callable.Callable) {
+	vm.natives[name] = value
+	vm.globals.Define(name, value)
+}
+
+// SetOutput redirects the output of print statements, which goes to os.Stdout by default.
+func (vm *VM) SetOutput(w io.Writer) {
+	vm.out = w
+}
+
+func (vm *VM) setLimiter(l *limiter) {
+	vm.limits = l
+}
+
+// SetPermissions grants capabilities to native functions that access the network,
+// files or the environment, and to imports of modules outside the script's directory.
+func (vm *VM) SetPermissions(permissions sandbox.Permissions) {
+	vm.permissions = permissions
+	vm.loader.permissions = permissions
+}
+
+func (vm *VM) setTestSuite(suite *testSuite) {
+	vm.tests = suite
+}
+
+// Globals returns the globals of the script being run.
+func (vm *VM) Globals() *environment.Environment {
+	return vm.globals
+}
+
+// SetScriptPath registers the file being run as the root module, relative imports
+// are resolved against its directory.
+func (vm *VM) SetScriptPath(path string) error {
+	return vm.loader.setScriptPath(path, vm.globals)
+}
+
+// EvaluateStmts compiles and runs resolved statements with the globals of the VM.
+func (vm *VM) EvaluateStmts(stmts []ast.Stmt) error {
+	return vm.runScript(stmts, vm.globals)
+}
+
+func (vm *VM) runModule(mod *module, stmts []ast.Stmt) error {
+	return vm.runScript(stmts, mod.globals)
+}
+
+func (vm *VM) runScript(stmts []ast.Stmt, globals *environment.Environment) error {
+	fn, err := compile(stmts, globals)
+	if err != nil {
+		return err
+	}
+
+	script := &closure{fn: fn, vm: vm}
+
+	vm.push(script)
+	vm.frames = append(vm.frames, callFrame{closure: script, base: len(vm.stack) - 1, name: errors.ScriptFrame})
+
+	if err := vm.run(len(vm.frames) - 1); err != nil {
+		return err
+	}
+
+	vm.pop()
+
+	return nil
+}
+
+func (vm *VM) push(value any) {
+	vm.stack = append(vm.stack, value)
+}
+
+func (vm *VM) pop() any {
+	value := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+
+	return value
+}
+
+func (vm *VM) peek(distance int) any {
+	return vm.stack[len(vm.stack)-1-distance]
+}
+
+// token returns the token of the instruction being executed by a frame. Calls made by
+// the host have no frame and no token.
+func (frame *callFrame) token() ast.Token {
+	if frame == nil {
+		return ast.Token{}
+	}
+
+	return frame.closure.fn.chunk.tokenAt(frame.ip - 1)
+}
+
+// run executes instructions until the frame at baseFrame returns. An error that is not
+// handled by a try region of these frames unwinds them and is returned.
+func (vm *VM) run(baseFrame int) error {
+	for {
+		frame := &vm.frames[len(vm.frames)-1]
+		chunk := &frame.closure.fn.chunk
+		op := opcode(chunk.code[frame.ip])
+		frame.ip++
+
+		var err error
+
+		switch op {
+		case opConstant:
+			vm.push(chunk.constants[vm.readOperand(frame)])
+		case opNil:
+			vm.push(nil)
+		case opTrue:
+			vm.push(true)
+		case opFalse:
+			vm.push(false)
+		case opPop:
+			vm.pop()
+		case opPopLocals:
+			sp := len(vm.stack) - vm.readOperand(frame)
+			vm.closeUpvalues(sp)
+			vm.stack = vm.stack[:sp]
+		case opPopBelow:
+			top := vm.pop()
+			sp := len(vm.stack) - vm.readOperand(frame)
+			vm.closeUpvalues(sp)
+			vm.stack = append(vm.stack[:sp], top)
+		case opGetLocal:
+			vm.push(vm.stack[frame.base+vm.readOperand(frame)])
+		case opSetLocal:
+			vm.stack[frame.base+vm.readOperand(frame)] = vm.peek(0)
+		case opGetUpvalue:
+			vm.push(vm.upvalueValue(frame.closure.upvalues[vm.readOperand(frame)]))
+		case opSetUpvalue:
+			vm.setUpvalue(frame.closure.upvalues[vm.readOperand(frame)], vm.peek(0))
+		case opDefineGlobal:
+			frame.closure.fn.globals.Define(chunk.constants[vm.readOperand(frame)].(string), vm.pop())
+		case opGetGlobal:
+			globals := frame.closure.fn.globals
+			value, ok := globals.Lookup(chunk.constants[vm.readOperand(frame)].(string))
+			if !ok {
+				_, err = globals.Get(frame.token())
+				break
+			}
+
+			vm.push(value)
+		case opSetGlobal:
+			globals := frame.closure.fn.globals
+			name := chunk.constants[vm.readOperand(frame)].(string)
+			if _, ok := globals.Lookup(name); !ok {
+				err = globals.Assign(frame.token(), vm.peek(0))
+				break
+			}
+
+			globals.Define(name, vm.peek(0))
+		case opGetProperty:
+			frame.ip += 2
+
+			var value any
+			if value, err = getProperty(vm.pop(), frame.token()); err == nil {
+				vm.push(value)
+			}
+		case opSetProperty:
+			frame.ip += 2
+			value := vm.pop()
+			target := vm.pop()
+
+			if !vm.limits.allocateKey(target, frame.token().Lexeme) {
+				err = vm.limits.exceeded(frame.token())
+			} else if err = setProperty(target, frame.token(), value); err == nil {
+				vm.push(value)
+			}
+		case opGetIndex:
+			index := vm.pop()
+
+			var value any
+			if value, err = getIndex(vm.pop(), index, frame.token()); err == nil {
+				vm.push(value)
+			}
+		case opSetIndex:
+			value := vm.pop()
+			index := vm.pop()
+			target := vm.pop()
+
+			if !vm.limits.allocateKey(target, index) {
+				err = vm.limits.exceeded(frame.token())
+			} else if err = setIndex(target, index, value, frame.token()); err == nil {
+				vm.push(value)
+			}
+		case opEqual:
+			right := vm.pop()
+			vm.push(helpers.IsEqual(vm.pop(), right))
+		case opNotEqual:
+			right := vm.pop()
+			vm.push(!helpers.IsEqual(vm.pop(), right))
+		case opAdd, opSubtract, opMultiply, opDivide, opGreater, opGreaterEqual, opLess, opLessEqual:
+			err = vm.binary(frame, op)
+		case opNot:
+			vm.push(!isTruthy(vm.pop()))
+		case opNegate:
+			if value, ok := vm.peek(0).(float64); ok {
+				vm.stack[len(vm.stack)-1] = -value
+				break
+			}
+
+			var value any
+			if value, err = negate(frame.token(), vm.pop()); err == nil {
+				vm.push(value)
+			}
+		case opPrint:
+			fmt.Fprintln(vm.out, Stringify(vm.pop()))
+		case opJump:
+			offset := vm.readOperand(frame)
+			frame.ip += offset
+		case opJumpIfFalse:
+			offset := vm.readOperand(frame)
+			if !isTruthy(vm.peek(0)) {
+				frame.ip += offset
+			}
+		case opLoop:
+			offset := vm.readOperand(frame)
+			if !vm.limits.step() {
+				err = vm.limits.exceeded(frame.token())
+				break
+			}
+
+			frame.ip -= offset
+		case opCall:
+			argCount := int(chunk.code[frame.ip])
+			frame.ip++
+			err = vm.call(frame, argCount)
+		case opClosure:
+			vm.push(vm.makeClosure(frame, chunk.constants[vm.readOperand(frame)].(*compiledFunction)))
+		case opReturn:
+			result := vm.pop()
+			vm.closeUpvalues(frame.base)
+			vm.stack = vm.stack[:frame.base]
+
+			if frame.isCall {
+				vm.depth--
+			}
+
+			vm.frames = vm.frames[:len(vm.frames)-1]
+			vm.push(result)
+
+			if len(vm.frames) == baseFrame {
+				return nil
+			}
+		case opClass:
+			vm.push(&vmClass{name: chunk.constants[vm.readOperand(frame)].(string), methods: map[string]*closure{}, vm: vm})
+		case opMethod:
+			name := chunk.constants[vm.readOperand(frame)].(string)
+			method := vm.pop().(*closure)
+			vm.peek(0).(*vmClass).methods[name] = method
+		case opArray:
+			count := vm.readOperand(frame)
+
+			var items []any
+			if count > 0 {
+				items = append(items, vm.stack[len(vm.stack)-count:]...)
+			}
+
+			vm.stack = vm.stack[:len(vm.stack)-count]
+
+			array := callable.NewArray(items)
+			vm.push(array)
+
+			if !vm.limits.allocate(array) {
+				err = vm.limits.exceeded(frame.token())
+			}
+		case opObject:
+			count := vm.readOperand(frame)
+			pairs := vm.stack[len(vm.stack)-2*count:]
+
+			obj := callable.NewObject()
+			for i := 0; i < len(pairs); i += 2 {
+				obj.Set(pairs[i].(string), pairs[i+1])
+			}
+
+			vm.stack = vm.stack[:len(vm.stack)-2*count]
+			vm.push(obj)
+
+			if !vm.limits.allocate(obj) {
+				err = vm.limits.exceeded(frame.token())
+			}
+		case opTry:
+			offset := vm.readOperand(frame)
+			vm.handlers = append(vm.handlers, handler{
+				frame:  len(vm.frames) - 1,
+				sp:     len(vm.stack),
+				target: frame.ip + offset,
+			})
+		case opEndTry:
+			vm.handlers = vm.handlers[:len(vm.handlers)-1]
+		case opCatch:
+			offset := vm.readOperand(frame)
+			if value, ok := caughtValue(vm.peek(0).(error)); ok {
+				vm.stack[len(vm.stack)-1] = value
+			} else {
+				frame.ip += offset
+			}
+		case opRethrow:
+			err = vm.pop().(error)
+		case opThrow:
+			err = errors.NewThrownError(frame.token(), vm.pop())
+		case opImport:
+			importStmt := chunk.constants[vm.readOperand(frame)].(*ast.ImportStmt)
+			err = vm.loader.importModule(importStmt, frame.closure.fn.globals.Define)
+		case opExport:
+			vm.loader.export(chunk.constants[vm.readOperand(frame)].(string))
+		case opTest:
+			testStmt := chunk.constants[vm.readOperand(frame)].(*ast.TestStmt)
+			vm.tests.add(vm.loader, testStmt, vm.pop())
+		default:
+			err = errors.NewRuntimeError(frame.token(), fmt.Sprintf("Unknown opcode %d.", op))
+		}
+
+		if err != nil {
+			if err := vm.raise(err, baseFrame); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (vm *VM) readOperand(frame *callFrame) int {
+	operand := frame.closure.fn.chunk.readUint16(frame.ip)
+	frame.ip += 2
+
+	return operand
+}
+
+// callFunction calls a function value from the host, the arguments have been checked
+// against its arity. It may run while a script is waiting for a native function.
+func (vm *VM) callFunction(fn any, args []any) (any, error) {
+	sp := len(vm.stack)
+	frameCount := len(vm.frames)
+
+	vm.push(fn)
+	vm.stack = append(vm.stack, args...)
+
+	if err := vm.call(nil, len(args)); err != nil {
+		vm.stack = vm.stack[:sp]
+		return nil, err
+	}
+
+	// Closures leave a frame to run, natives and classes without an initializer are done.
+	if len(vm.frames) > frameCount {
+		vm.frames[frameCount].fromHost = true
+
+		if err := vm.run(frameCount); err != nil {
+			return nil, err
+		}
+	}
+
+	return vm.pop(), nil
+}
+
+// callBack calls a function passed to a native function.
+func (vm *VM) callBack(fn callable.Callable, args []any, _ ast.Token) (any, error) {
+	return vm.callFunction(fn, args)
+}
+
+// arity returns the number of arguments a function value expects, -1 for any number.
+func (vm *VM) arity(fn any) (int, bool) {
+	if callee, ok := fn.(callable.Callable); ok {
+		return callee.Arity(), true
+	}
+
+	return 0, false
+}
+
+// binary runs an arithmetic or comparison instruction, numbers take a fast path and
+// everything else goes through the operations shared with the interpreter.
+func (vm *VM) binary(frame *callFrame, op opcode) error {
+	left, leftOk := vm.peek(1).(float64)
+	right, rightOk := vm.peek(0).(float64)
+
+	if leftOk && rightOk {
+		var result any
+
+		switch op {
+		case opAdd:
+			result = left + right
+		case opSubtract:
+			result = left - right
+		case opMultiply:
+			result = left * right
+		case opDivide:
+			result = left / right
+		case opGreater:
+			result = left > right
+		case opGreaterEqual:
+			result = left >= right
+		case opLess:
+			result = left < right
+		case opLessEqual:
+			result = left <= right
+		}
+
+		vm.stack = vm.stack[:len(vm.stack)-1]
+		vm.stack[len(vm.stack)-1] = result
+
+		return nil
+	}
+
+	r := vm.pop()
+	l := vm.pop()
+
+	result, err := binary(frame.token(), l, r)
+	if err != nil {
+		return err
+	}
+
+	vm.push(result)
+
+	if !vm.limits.allocate(result) {
+		return vm.limits.exceeded(frame.token())
+	}
+
+	return nil
+}
+
+func isTruthy(value any) bool {
+	switch value.(type) {
+	case *closure, *boundMethod, *vmClass, *vmInstance:
+		return true
+	}
+
+	return helpers.IsTruthy(value)
+}
+
+// call calls the callee below the arguments on top of the stack.
+func (vm *VM) call(frame *callFrame, argCount int) error {
+	calleeSlot := len(vm.stack) - 1 - argCount
+
+	switch callee := vm.stack[calleeSlot].(type) {
+	case *closure:
+		return vm.callClosure(frame, callee, argCount, callee.fn.name)
+	case *boundMethod:
+		vm.stack[calleeSlot] = callee.receiver
+		return vm.callClosure(frame, callee.method, argCount, callee.method.fn.name)
+	case *vmClass:
+		vm.stack[calleeSlot] = newVMInstance(callee)
+
+		if initializer, ok := callee.methods[callable.InitializerName]; ok {
+			return vm.callClosure(frame, initializer, argCount, callee.name)
+		}
+
+		if err := vm.checkCall(frame, 0, argCount); err != nil {
+			return err
+		}
+	case callable.Callable:
+		if err := vm.checkCall(frame, callee.Arity(), argCount); err != nil {
+			return err
+		}
+
+		args := append([]any{}, vm.stack[calleeSlot+1:]...)
+
+		err := authorize(vm.permissions, callee, args, frame.token())
+
+		var result any
+		if err == nil {
+			result, err = callNative(callee, nil, vm.callBack, args, frame.token())
+		}
+
+		if err == nil && !vm.limits.allocateResult(callee, args, result) {
+			err = vm.limits.exceeded(frame.token())
+		}
+
+		if err != nil {
+			return vm.withTrace(err, frameName(callee))
+		}
+
+		vm.stack = vm.stack[:calleeSlot]
+		vm.push(result)
+	default:
+		return errors.NewRuntimeError(frame.token(), "Can only call functions.")
+	}
+
+	return nil
+}
+
+// checkCall reports a wrong number of arguments and a too deep recursion, in the order
+// the interpreter does.
+func (vm *VM) checkCall(frame *callFrame, arity int, argCount int) error {
+	if arity != -1 && argCount != arity {
+		return errors.NewRuntimeError(
+			frame.token(),
+			fmt.Sprintf("Expected %d arguments but got %d.", arity, argCount),
+		)
+	}
+
+	if !vm.limits.step() {
+		return vm.limits.exceeded(frame.token())
+	}
+
+	if vm.depth >= vm.maxRecursion {
+		return errors.NewRuntimeError(frame.token(), "Stack overflow.")
+	}
+
+	return nil
+}
+
+func (vm *VM) callClosure(frame *callFrame, callee *closure, argCount int, name string) error {
+	if err := vm.checkCall(frame, callee.fn.arity, argCount); err != nil {
+		return err
+	}
+
+	vm.depth++
+	vm.frames = append(vm.frames, callFrame{
+		closure: callee,
+		base:    len(vm.stack) - 1 - argCount,
+		name:    name,
+		isCall:  true,
+	})
+
+	return nil
+}
+
+func (vm *VM) makeClosure(frame *callFrame, fn *compiledFunction) *closure {
+	code := frame.closure.fn.chunk.code
+	result := &closure{fn: fn, upvalues: make([]*upvalue, fn.upvalueCount), vm: vm}
+
+	for i := range result.upvalues {
+		isLocal := code[frame.ip] == 1
+		index := frame.closure.fn.chunk.readUint16(frame.ip + 1)
+		frame.ip += 3
+
+		if isLocal {
+			result.upvalues[i] = vm.captureUpvalue(frame.base + index)
+		} else {
+			result.upvalues[i] = frame.closure.upvalues[index]
+		}
+	}
+
+	return result
+}
+
+// captureUpvalue returns the open upvalue of a stack slot, creating it if needed.
+func (vm *VM) captureUpvalue(slot int) *upvalue {
+	var prev *upvalue
+
+	current := vm.openUpvalues
+	for current != nil && current.slot > slot {
+		prev = current
+		current = current.next
+	}
+
+	if current != nil && current.slot == slot {
+		return current
+	}
+
+	created := &upvalue{slot: slot, next: current}
+
+	if prev == nil {
+		vm.openUpvalues = created
+	} else {
+		prev.next = created
+	}
+
+	return created
+}
+
+// closeUpvalues moves the values of the slots at or above sp into their upvalues.
+func (vm *VM) closeUpvalues(sp int) {
+	for vm.openUpvalues != nil && vm.openUpvalues.slot >= sp {
+		u := vm.openUpvalues
+		u.value = vm.stack[u.slot]
+		u.closed = true
+		vm.openUpvalues = u.next
+	}
+}
+
+func (vm *VM) upvalueValue(u *upvalue) any {
+	if u.closed {
+		return u.value
+	}
+
+	return vm.stack[u.slot]
+}
+
+func (vm *VM) setUpvalue(u *upvalue, value any) {
+	if u.closed {
+		u.value = value
+	} else {
+		vm.stack[u.slot] = value
+	}
+}
+
+// raise unwinds to the innermost try region of the frames run since baseFrame, or
+// unwinds all of them and returns the error.
+func (vm *VM) raise(err error, baseFrame int) error {
+	err = vm.withTrace(err, "")
+
+	if n := len(vm.handlers); n > 0 && vm.handlers[n-1].frame >= baseFrame {
+		h := vm.handlers[n-1]
+		vm.handlers = vm.handlers[:n-1]
+
+		vm.unwind(h.frame+1, h.sp)
+		vm.push(err)
+		vm.frames[h.frame].ip = h.target
+
+		return nil
+	}
+
+	vm.unwind(baseFrame, vm.frames[baseFrame].base)
+
+	return err
+}
+
+func (vm *VM) unwind(frameCount int, sp int) {
+	vm.closeUpvalues(sp)
+	vm.stack = vm.stack[:sp]
+
+	for len(vm.frames) > frameCount {
+		if vm.frames[len(vm.frames)-1].isCall {
+			vm.depth--
+		}
+
+		vm.frames = vm.frames[:len(vm.frames)-1]
+	}
+}
+
+// withTrace attaches the active calls to an error raised inside a call, the same way the
+// interpreter does. native is the name of the native function that failed, if any.
+func (vm *VM) withTrace(err error, native string) error {
+	e, ok := err.(traced)
+	if !ok || e.Trace() != nil {
+		return err
+	}
+
+	top := len(vm.frames) - 1
+	if top < 0 || native == "" && !vm.frames[top].isCall {
+		return err
+	}
+
+	var trace []errors.Frame
+
+	if native != "" {
+		trace = append(trace, errors.Frame{Function: native, Line: e.Line()})
+	}
+
+	for i := top; i >= 0; i-- {
+		frame := &vm.frames[i]
+
+		line := frame.token().Line
+		if i == top && native == "" {
+			line = e.Line()
+		}
+
+		trace = append(trace, errors.Frame{Function: frame.name, Line: line})
+
+		if !frame.isCall || frame.fromHost {
+			break
+		}
+	}
+
+	return e.WithTrace(trace)
+}
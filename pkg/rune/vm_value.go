@@ -0,0 +1,136 @@
+package rune
+
+import (
+	"fmt"
+	"rune/pkg/ast"
+	"rune/pkg/callable"
+	"rune/pkg/environment"
+	"rune/pkg/errors"
+)
+
+// compiledFunction is a function compiled to bytecode, it becomes a value once it is
+// wrapped in a closure.
+type compiledFunction struct {
+	name         string
+	arity        int
+	upvalueCount int
+	isScript     bool
+	chunk        chunk
+	// globals are the globals of the module the function was declared in.
+	globals *environment.Environment
+}
+
+// closure is a function value of the VM together with the variables it captured.
+// Function values of the VM are callables too, so native functions can call them back.
+type closure struct {
+	fn       *compiledFunction
+	upvalues []*upvalue
+	vm       *VM
+}
+
+func (c *closure) Call(_ callable.ExecuteBlockFn, args []any, _ ast.Token) (any, error) {
+	return c.vm.callFunction(c, args)
+}
+
+func (c *closure) Arity() int {
+	return c.fn.arity
+}
+
+func (c *closure) Name() string {
+	return c.fn.name
+}
+
+func (c *closure) String() string {
+	if c.fn.isScript {
+		return errors.ScriptFrame
+	}
+
+	return fmt.Sprintf("<fn %s>", c.fn.name)
+}
+
+// upvalue is a captured variable. It refers to a stack slot while the variable is in scope
+// and holds the value itself once the variable is closed over.
+type upvalue struct {
+	slot   int
+	closed bool
+	value  any
+	// next links the open upvalues in order of decreasing slot.
+	next *upvalue
+}
+
+type vmClass struct {
+	name    string
+	methods map[string]*closure
+	vm      *VM
+}
+
+func (c *vmClass) Call(_ callable.ExecuteBlockFn, args []any, _ ast.Token) (any, error) {
+	return c.vm.callFunction(c, args)
+}
+
+func (c *vmClass) Arity() int {
+	if initializer, ok := c.methods[callable.InitializerName]; ok {
+		return initializer.fn.arity
+	}
+
+	return 0
+}
+
+func (c *vmClass) Name() string {
+	return c.name
+}
+
+func (c *vmClass) String() string {
+	return c.name
+}
+
+type vmInstance struct {
+	class  *vmClass
+	fields map[string]any
+}
+
+func newVMInstance(class *vmClass) *vmInstance {
+	return &vmInstance{class: class, fields: map[string]any{}}
+}
+
+// Get looks up a field first and falls back to a method bound to the instance.
+func (i *vmInstance) Get(name string, token ast.Token) (any, error) {
+	if value, ok := i.fields[name]; ok {
+		return value, nil
+	}
+
+	if method, ok := i.class.methods[name]; ok {
+		return &boundMethod{receiver: i, method: method}, nil
+	}
+
+	return nil, errors.NewRuntimeError(token, fmt.Sprintf("Undefined property '%s'.", name))
+}
+
+func (i *vmInstance) Set(name string, value any) {
+	i.fields[name] = value
+}
+
+func (i *vmInstance) String() string {
+	return fmt.Sprintf("%s instance", i.class.name)
+}
+
+type boundMethod struct {
+	receiver *vmInstance
+	method   *closure
+}
+
+func (b *boundMethod) Call(_ callable.ExecuteBlockFn, args []any, _ ast.Token) (any, error) {
+	return b.method.vm.callFunction(b, args)
+}
+
+func (b *boundMethod) Arity() int {
+	return b.method.fn.arity
+}
+
+func (b *boundMethod) Name() string {
+	return b.method.fn.name
+}
+
+func (b *boundMethod) String() string {
+	return b.method.String()
+}
@@ -0,0 +1,122 @@
+package sandbox
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Kind is a kind of access a native function can need.
+type Kind string
+
+const (
+	// Net is access to a network host, the target is "host" or "host:port".
+	Net Kind = "net"
+	// Read is read access to a file, the target is its path.
+	Read Kind = "read"
+	// Env is access to an environment variable, the target is its name.
+	Env Kind = "env"
+)
+
+// All grants every target of a kind.
+const All = "*"
+
+// Request is an access a native function needs for one call.
+type Request struct {
+	Kind   Kind
+	Target string
+}
+
+func (r Request) String() string {
+	switch r.Kind {
+	case Env:
+		return fmt.Sprintf("access to the environment variable '%s'", r.Target)
+	default:
+		return fmt.Sprintf("%s access to '%s'", r.Kind, r.Target)
+	}
+}
+
+// Permissions are the capabilities granted to a program. Nothing is granted by default.
+type Permissions struct {
+	// Net lists the hosts that can be reached. A host without a port allows every port.
+	Net []string
+	// Read lists the directories whose files can be read, with their subdirectories.
+	Read []string
+	// Env lists the environment variables that can be read.
+	Env []string
+}
+
+// AllowAll returns permissions that grant everything.
+func AllowAll() Permissions {
+	return Permissions{Net: []string{All}, Read: []string{All}, Env: []string{All}}
+}
+
+// Allows reports whether the permissions grant a request.
+func (p Permissions) Allows(r Request) bool {
+	switch r.Kind {
+	case Net:
+		return allowsHost(p.Net, r.Target)
+	case Read:
+		return allowsPath(p.Read, r.Target)
+	case Env:
+		return slices.Contains(p.Env, All) || slices.Contains(p.Env, r.Target)
+	default:
+		return false
+	}
+}
+
+func allowsHost(granted []string, target string) bool {
+	host := target
+	if h, _, err := net.SplitHostPort(target); err == nil {
+		host = h
+	}
+
+	for _, g := range granted {
+		if g == All || strings.EqualFold(g, target) || strings.EqualFold(g, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func allowsPath(granted []string, target string) bool {
+	path, err := resolvePath(target)
+	if err != nil {
+		return false
+	}
+
+	for _, g := range granted {
+		if g == All {
+			return true
+		}
+
+		root, err := resolvePath(g)
+		if err != nil {
+			continue
+		}
+
+		if rel, err := filepath.Rel(root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolvePath returns the absolute path with symlinks resolved, so a link cannot lead
+// out of a granted directory.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	}
+
+	return abs, nil
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"rune/pkg/ast"
+	"rune/pkg/rune"
+	"rune/pkg/sandbox"
+	"strings"
+)
+
+const (
+	replPrompt         = "> "
+	replContinuePrompt = "... "
+	replHistoryFile    = ".rune_history"
+)
+
+// repl keeps a single interpreter and resolver alive across inputs, so declarations
+// from earlier lines stay visible to later ones.
+type repl struct {
+	interpreter *rune.Interpreter
+	resolver    *rune.Resolver
+	history     []string
+	historyPath string
+	in          *bufio.Scanner
+	out         io.Writer
+	errOut      io.Writer
+}
+
+func newRepl(in io.Reader, out io.Writer, errOut io.Writer) *repl {
+	interpreter := rune.NewInterpreter()
+	interpreter.SetOutput(out)
+	// The session is typed by the user, it is not sandboxed.
+	interpreter.SetPermissions(sandbox.AllowAll())
+
+	r := &repl{
+		interpreter: interpreter,
+		resolver:    rune.NewResolver(interpreter),
+		in:          bufio.NewScanner(in),
+		out:         out,
+		errOut:      errOut,
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		r.historyPath = filepath.Join(home, replHistoryFile)
+		r.loadHistory()
+	}
+
+	return r
+}
+
+func startRepl() int {
+	fmt.Fprintf(os.Stdout, "Rune Interpreter v%s\n", version)
+	fmt.Fprintf(os.Stdout, "Type :help for a list of commands, :quit to exit.\n")
+
+	return newRepl(os.Stdin, os.Stdout, os.Stderr).run()
+}
+
+func (r *repl) run() int {
+	for {
+		input, ok := r.readInput()
+		if !ok {
+			fmt.Fprintln(r.out)
+			return exitCodeOk
+		}
+
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+
+		r.addHistory(input)
+
+		if strings.HasPrefix(strings.TrimSpace(input), ":") {
+			if quit := r.command(strings.TrimSpace(input)); quit {
+				return exitCodeOk
+			}
+
+			continue
+		}
+
+		r.eval(input)
+	}
+}
+
+// readInput reads one entry, continuing onto further lines while brackets are unbalanced.
+func (r *repl) readInput() (string, bool) {
+	fmt.Fprint(r.out, replPrompt)
+
+	var lines []string
+
+	for r.in.Scan() {
+		lines = append(lines, r.in.Text())
+		input := strings.Join(lines, "\n")
+
+		if strings.HasPrefix(strings.TrimSpace(input), ":") || !isIncomplete(input) {
+			return input, true
+		}
+
+		fmt.Fprint(r.out, replContinuePrompt)
+	}
+
+	if len(lines) > 0 {
+		return strings.Join(lines, "\n"), true
+	}
+
+	return "", false
+}
+
+// isIncomplete reports whether the input has unclosed brackets or an unterminated string.
+func isIncomplete(input string) bool {
+	tokens, errors := rune.Scan([]byte(input))
+
+	for _, err := range errors {
+		if strings.Contains(err.Error(), "Unterminated string.") {
+			return true
+		}
+	}
+
+	depth := 0
+
+	for _, token := range tokens {
+		switch token.TokenType {
+		case ast.LEFT_BRACE, ast.LEFT_PAREN, ast.LEFT_BRACKET:
+			depth++
+		case ast.RIGHT_BRACE, ast.RIGHT_PAREN, ast.RIGHT_BRACKET:
+			depth--
+		}
+	}
+
+	return depth > 0
+}
+
+func (r *repl) eval(input string) {
+	stmts, errors := r.parse(input)
+	if len(errors) > 0 {
+		for _, err := range errors {
+			printError(r.errOut, err)
+		}
+
+		return
+	}
+
+	if errors := r.resolver.ResolveStmts(stmts); len(errors) > 0 {
+		for _, err := range errors {
+			printError(r.errOut, err)
+		}
+
+		return
+	}
+
+	// A lone expression has its value printed back.
+	if len(stmts) == 1 {
+		if exprStmt, ok := stmts[0].(*ast.ExprStmt); ok {
+			value, err := r.interpreter.Evaluate(exprStmt.Expr)
+			if err != nil {
+				printError(r.errOut, err)
+				return
+			}
+
+			if value != nil {
+				fmt.Fprintln(r.out, rune.Stringify(value))
+			}
+
+			return
+		}
+	}
+
+	if err := r.interpreter.EvaluateStmts(stmts); err != nil {
+		printError(r.errOut, err)
+	}
+}
+
+// parse scans and parses the input, an expression may be entered without its trailing ';'.
+func (r *repl) parse(input string) ([]ast.Stmt, []error) {
+	tokens, errors := rune.Scan([]byte(input))
+	if len(errors) > 0 {
+		return nil, errors
+	}
+
+	stmts, errors := rune.ParseStmts(tokens)
+	if len(errors) == 0 {
+		return stmts, nil
+	}
+
+	tokens, scanErrors := rune.Scan([]byte(input + ";"))
+	if len(scanErrors) > 0 {
+		return nil, errors
+	}
+
+	if withSemicolon, semicolonErrors := rune.ParseStmts(tokens); len(semicolonErrors) == 0 {
+		return withSemicolon, nil
+	}
+
+	return nil, errors
+}
+
+// command runs a meta-command and reports whether the REPL should exit.
+func (r *repl) command(input string) bool {
+	name, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case ":quit", ":exit":
+		return true
+	case ":help":
+		r.printHelp()
+	case ":tokens":
+		r.printTokens(arg)
+	case ":ast":
+		r.printAst(arg)
+	case ":env":
+		r.printEnv()
+	case ":history":
+		for i, entry := range r.history {
+			fmt.Fprintf(r.out, "%4d  %s\n", i+1, entry)
+		}
+	default:
+		fmt.Fprintf(r.errOut, "Unknown command: %s\n", name)
+	}
+
+	return false
+}
+
+func (r *repl) printHelp() {
+	fmt.Fprintf(r.out, "Commands:\n")
+	fmt.Fprintf(r.out, "  :tokens <code>  - Prints the tokens of the code\n")
+	fmt.Fprintf(r.out, "  :ast <code>     - Prints the syntax tree of the code\n")
+	fmt.Fprintf(r.out, "  :env            - Prints the global variables\n")
+	fmt.Fprintf(r.out, "  :history        - Prints the previous inputs\n")
+	fmt.Fprintf(r.out, "  :help           - Prints this help\n")
+	fmt.Fprintf(r.out, "  :quit           - Exits the REPL\n")
+}
+
+func (r *repl) printTokens(code string) {
+	tokens, errors := rune.Scan([]byte(code))
+	for _, token := range tokens {
+		fmt.Fprintln(r.out, token)
+	}
+
+	for _, err := range errors {
+		printError(r.errOut, err)
+	}
+}
+
+func (r *repl) printAst(code string) {
+	stmts, errors := r.parse(code)
+	if len(errors) > 0 {
+		for _, err := range errors {
+			printError(r.errOut, err)
+		}
+
+		return
+	}
+
+	printer := ast.NewPrinter()
+	for _, stmt := range stmts {
+		fmt.Fprintln(r.out, printer.PrintStmt(stmt))
+	}
+}
+
+func (r *repl) printEnv() {
+	globals := r.interpreter.Globals()
+
+	for _, name := range globals.Names() {
+		value, _ := globals.Lookup(name)
+		fmt.Fprintf(r.out, "%s = %s\n", name, rune.Stringify(value))
+	}
+}
+
+func (r *repl) loadHistory() {
+	content, err := os.ReadFile(r.historyPath)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range strings.Split(string(content), "\x00") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			r.history = append(r.history, entry)
+		}
+	}
+}
+
+// addHistory records an entry and appends it to the history file, entries are
+// separated by NUL bytes because they may span several lines.
+func (r *repl) addHistory(entry string) {
+	r.history = append(r.history, entry)
+
+	if r.historyPath == "" {
+		return
+	}
+
+	file, err := os.OpenFile(r.historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	fmt.Fprintf(file, "%s\x00", entry)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runRepl runs a session on the input and returns what it printed to out and errOut.
+// The history is written to a temporary home directory.
+func runRepl(t *testing.T, input string) (string, string) {
+	t.Helper()
+
+	return runReplIn(t, t.TempDir(), input)
+}
+
+// runReplIn runs a session with its history in the home directory.
+func runReplIn(t *testing.T, home string, input string) (string, string) {
+	t.Helper()
+
+	t.Setenv("HOME", home)
+
+	var out, errOut bytes.Buffer
+	if code := newRepl(strings.NewReader(input), &out, &errOut).run(); code != exitCodeOk {
+		t.Errorf("got exit code %d", code)
+	}
+
+	return out.String(), errOut.String()
+}
+
+func TestReplMultiLine(t *testing.T) {
+	out, errOut := runRepl(t, "fun add(a, b) {\n  return a + b;\n}\nprint add(1, 2);\n")
+
+	if want := "> ... ... > 3\n> \n"; out != want || errOut != "" {
+		t.Errorf("got output %q and errors %q, want %q", out, errOut, want)
+	}
+}
+
+func TestReplUnterminatedString(t *testing.T) {
+	out, _ := runRepl(t, "print \"a\nb\";\n")
+
+	if want := "> ... a\nb\n> \n"; out != want {
+		t.Errorf("got output %q, want %q", out, want)
+	}
+}
+
+func TestReplExpression(t *testing.T) {
+	out, errOut := runRepl(t, "var a = 2;\na * 3\n\"text\"\nnil\n")
+
+	// Values are printed back, nil is not.
+	if want := "> > 6\n> text\n> > \n"; out != want || errOut != "" {
+		t.Errorf("got output %q and errors %q, want %q", out, errOut, want)
+	}
+}
+
+func TestReplErrorKeepsSession(t *testing.T) {
+	out, errOut := runRepl(t, "var a = 1;\nprint nope;\nprint a +;\nprint a;\n")
+
+	if !strings.HasSuffix(out, "> 1\n> \n") {
+		t.Errorf("got output %q, want the session to go on after the errors", out)
+	}
+
+	if !strings.Contains(errOut, "Undefined variable 'nope'.") || !strings.Contains(errOut, "Error at ';': Expect expression.") {
+		t.Errorf("got errors %q", errOut)
+	}
+}
+
+func TestReplCommands(t *testing.T) {
+	out, errOut := runRepl(t, ":tokens var a = 1;\n:ast print 1 + 2;\nvar answer = 42;\n:env\n:nope\n:quit\nprint 1;\n")
+
+	for _, want := range []string{"VAR var null\n", "IDENTIFIER a null\n", "NUMBER 1 1.0\n", "(print (+ 1.0 2.0))\n", "answer = 42\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q does not contain %q", out, want)
+		}
+	}
+
+	if errOut != "Unknown command: :nope\n" {
+		t.Errorf("got errors %q", errOut)
+	}
+
+	// Nothing runs after :quit.
+	if strings.HasSuffix(out, "1\n") {
+		t.Errorf("got output %q after :quit", out)
+	}
+}
+
+func TestReplHistory(t *testing.T) {
+	home := t.TempDir()
+
+	runReplIn(t, home, "print 1;\nfun f() {\n  return 2;\n}\n")
+
+	content, err := os.ReadFile(filepath.Join(home, replHistoryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "print 1;\x00fun f() {\n  return 2;\n}\x00"; string(content) != want {
+		t.Errorf("got history %q, want %q", content, want)
+	}
+
+	// A new session loads the entries of the previous ones.
+	out, _ := runReplIn(t, home, ":history\n")
+	if want := "   1  print 1;\n   2  fun f() {\n  return 2;\n}\n   3  :history\n"; !strings.Contains(out, want) {
+		t.Errorf("got output %q, want %q", out, want)
+	}
+}
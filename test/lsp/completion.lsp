@@ -0,0 +1,15 @@
+// Completion offers the locals in scope, globals, natives and keywords starting with
+// the identifier at the cursor.
+--> {"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{}}}
+<-- {"jsonrpc":"2.0","id":1,"result":{}}
+--> {"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///c.rn","languageId":"rune","version":1,"text":"var total = 1;\nfun f(tag) {\n  var tally = 2;\n  print ta;\n}\nprint t;\n"}}}
+<-- {"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///c.rn","diagnostics":[]}}
+--> {"jsonrpc":"2.0","id":2,"method":"textDocument/completion","params":{"textDocument":{"uri":"file:///c.rn"},"position":{"line":3,"character":10}}}
+<-- {"jsonrpc":"2.0","id":2,"result":[{"label":"tag","kind":6},{"label":"tally","kind":6}]}
+--> {"jsonrpc":"2.0","id":3,"method":"textDocument/completion","params":{"textDocument":{"uri":"file:///c.rn"},"position":{"line":5,"character":7}}}
+<-- {"jsonrpc":"2.0","id":3,"result":[{"label":"this","kind":14},{"label":"throw","kind":14},{"label":"total","kind":6},{"label":"true","kind":14},{"label":"try","kind":14}]}
+--> {"jsonrpc":"2.0","id":4,"method":"textDocument/completion","params":{"textDocument":{"uri":"file:///c.rn"},"position":{"line":3,"character":9}}}
+<-- {"jsonrpc":"2.0","id":4,"result":[{"label":"tag","kind":6},{"label":"tally","kind":6},{"label":"this","kind":14},{"label":"throw","kind":14},{"label":"total","kind":6},{"label":"true","kind":14},{"label":"try","kind":14}]}
+--> {"jsonrpc":"2.0","id":99,"method":"shutdown"}
+<-- {"jsonrpc":"2.0","id":99,"result":null}
+--> {"jsonrpc":"2.0","method":"exit"}
@@ -0,0 +1,15 @@
+// Syntax errors are reported first, then resolve errors once the syntax is valid.
+--> {"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{}}}
+<-- {"jsonrpc":"2.0","id":1,"result":{}}
+--> {"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///a.rn","languageId":"rune","version":1,"text":"var a = 1;\nprint a +;\nprint \"ok\" @;\n"}}}
+<-- {"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.rn","diagnostics":[{"range":{"start":{"line":2,"character":11},"end":{"line":2,"character":12}},"severity":1,"source":"rune","message":"Error: Unexpected character: @"},{"range":{"start":{"line":1,"character":9},"end":{"line":1,"character":10}},"severity":1,"source":"rune","message":"Error at ';': Expect expression."}]}}
+--> {"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///a.rn","version":2},"contentChanges":[{"text":"fun f() {\n  var x = 1;\n  var x = 2;\n}\nreturn 1;\n"}]}}
+<-- {"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.rn","diagnostics":[{"range":{"start":{"line":2,"character":6},"end":{"line":2,"character":7}},"severity":1,"source":"rune","message":"Error at 'x': Variable with this name already declared in this scope."},{"range":{"start":{"line":4,"character":0},"end":{"line":4,"character":6}},"severity":1,"source":"rune","message":"Error at 'return': Cannot return from top-level code."}]}}
+// Columns count UTF-16 code units.
+--> {"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///a.rn","version":3},"contentChanges":[{"text":"print \"héllo\"; print \"😀\" +;\n"}]}}
+<-- {"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.rn","diagnostics":[{"range":{"start":{"line":0,"character":27},"end":{"line":0,"character":28}},"severity":1,"source":"rune","message":"Error at ';': Expect expression."}]}}
+--> {"jsonrpc":"2.0","method":"textDocument/didClose","params":{"textDocument":{"uri":"file:///a.rn"}}}
+<-- {"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.rn","diagnostics":[]}}
+--> {"jsonrpc":"2.0","id":2,"method":"shutdown"}
+<-- {"jsonrpc":"2.0","id":2,"result":null}
+--> {"jsonrpc":"2.0","method":"exit"}
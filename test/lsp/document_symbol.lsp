@@ -0,0 +1,11 @@
+// Document symbols list the functions and classes, methods and nested functions as
+// children.
+--> {"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{}}}
+<-- {"jsonrpc":"2.0","id":1,"result":{}}
+--> {"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///nav.rn","languageId":"rune","version":1,"text":"fun add(a, b) {\n  var sum = a + b;\n  return sum;\n}\nclass Point {\n  init(x, y) {\n    this.x = x;\n  }\n  norm() {\n    fun square(n) { return n * n; }\n    return square(this.x);\n  }\n}\nvar total = add(1, 2);\nprint len([total]);\nprint add(total, 3);\nvar p = Point(1, 2);\n"}}}
+<-- {"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///nav.rn","diagnostics":[]}}
+--> {"jsonrpc":"2.0","id":2,"method":"textDocument/documentSymbol","params":{"textDocument":{"uri":"file:///nav.rn"}}}
+<-- {"jsonrpc":"2.0","id":2,"result":[{"name":"add","detail":"fun add(a, b)","kind":12,"range":{"start":{"line":0,"character":4},"end":{"line":3,"character":1}},"selectionRange":{"start":{"line":0,"character":4},"end":{"line":0,"character":7}}},{"name":"Point","kind":5,"range":{"start":{"line":4,"character":6},"end":{"line":12,"character":1}},"selectionRange":{"start":{"line":4,"character":6},"end":{"line":4,"character":11}},"children":[{"name":"init","detail":"init(x, y)","kind":6,"range":{"start":{"line":5,"character":2},"end":{"line":7,"character":3}},"selectionRange":{"start":{"line":5,"character":2},"end":{"line":5,"character":6}}},{"name":"norm","detail":"norm()","kind":6,"range":{"start":{"line":8,"character":2},"end":{"line":11,"character":3}},"selectionRange":{"start":{"line":8,"character":2},"end":{"line":8,"character":6}},"children":[{"name":"square","detail":"fun square(n)","kind":12,"range":{"start":{"line":9,"character":8},"end":{"line":9,"character":35}},"selectionRange":{"start":{"line":9,"character":8},"end":{"line":9,"character":14}}}]}]}]}
+--> {"jsonrpc":"2.0","id":99,"method":"shutdown"}
+<-- {"jsonrpc":"2.0","id":99,"result":null}
+--> {"jsonrpc":"2.0","method":"exit"}
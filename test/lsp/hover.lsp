@@ -0,0 +1,21 @@
+// Hovers show the signature and arity of functions, classes and natives, natives
+// with their docs.
+--> {"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{}}}
+<-- {"jsonrpc":"2.0","id":1,"result":{}}
+--> {"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///nav.rn","languageId":"rune","version":1,"text":"fun add(a, b) {\n  var sum = a + b;\n  return sum;\n}\nclass Point {\n  init(x, y) {\n    this.x = x;\n  }\n  norm() {\n    fun square(n) { return n * n; }\n    return square(this.x);\n  }\n}\nvar total = add(1, 2);\nprint len([total]);\nprint add(total, 3);\nvar p = Point(1, 2);\n"}}}
+<-- {"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///nav.rn","diagnostics":[]}}
+--> {"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":15,"character":7}}}
+<-- {"jsonrpc":"2.0","id":2,"result":{"contents":{"kind":"markdown","value":"```rune\nfun add(a, b)\n```\nArity: 2"},"range":{"start":{"line":15,"character":6},"end":{"line":15,"character":9}}}}
+--> {"jsonrpc":"2.0","id":3,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":16,"character":9}}}
+<-- {"jsonrpc":"2.0","id":3,"result":{"contents":{"kind":"markdown","value":"```rune\nclass Point(x, y)\n```\nArity: 2"},"range":{"start":{"line":16,"character":8},"end":{"line":16,"character":13}}}}
+--> {"jsonrpc":"2.0","id":4,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":14,"character":6}}}
+<-- {"jsonrpc":"2.0","id":4,"result":{"contents":{"kind":"markdown","value":"```rune\nlen(value)\n```\nReturns the length of an array or a string.\n\nArity: 1"},"range":{"start":{"line":14,"character":6},"end":{"line":14,"character":9}}}}
+--> {"jsonrpc":"2.0","id":5,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":1,"character":12}}}
+<-- {"jsonrpc":"2.0","id":5,"result":{"contents":{"kind":"markdown","value":"```rune\na\n```\nParameter"},"range":{"start":{"line":1,"character":12},"end":{"line":1,"character":13}}}}
+--> {"jsonrpc":"2.0","id":6,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":13,"character":4}}}
+<-- {"jsonrpc":"2.0","id":6,"result":{"contents":{"kind":"markdown","value":"```rune\nvar total\n```\nGlobal variable"},"range":{"start":{"line":13,"character":4},"end":{"line":13,"character":9}}}}
+--> {"jsonrpc":"2.0","id":7,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":0,"character":0}}}
+<-- {"jsonrpc":"2.0","id":7,"result":null}
+--> {"jsonrpc":"2.0","id":99,"method":"shutdown"}
+<-- {"jsonrpc":"2.0","id":99,"result":null}
+--> {"jsonrpc":"2.0","method":"exit"}
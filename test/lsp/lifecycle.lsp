@@ -0,0 +1,12 @@
+// Requests before initialize are refused, unknown methods are reported.
+--> {"jsonrpc":"2.0","id":1,"method":"textDocument/hover","params":{}}
+<-- {"jsonrpc":"2.0","id":1,"error":{"code":-32002}}
+--> {"jsonrpc":"2.0","id":2,"method":"initialize","params":{"capabilities":{}}}
+<-- {"jsonrpc":"2.0","id":2,"result":{"capabilities":{"textDocumentSync":1,"definitionProvider":true,"referencesProvider":true,"hoverProvider":true,"completionProvider":{},"documentSymbolProvider":true},"serverInfo":{"name":"rune"}}}
+--> {"jsonrpc":"2.0","method":"initialized","params":{}}
+--> {"jsonrpc":"2.0","id":3,"method":"workspace/symbol","params":{"query":""}}
+<-- {"jsonrpc":"2.0","id":3,"error":{"code":-32601,"message":"Method 'workspace/symbol' not found."}}
+--> {"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":3}}
+--> {"jsonrpc":"2.0","id":4,"method":"shutdown"}
+<-- {"jsonrpc":"2.0","id":4,"result":null}
+--> {"jsonrpc":"2.0","method":"exit"}
@@ -0,0 +1,21 @@
+// Go to definition and find references use the scopes of the resolver. Natives have
+// no definition.
+--> {"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{}}}
+<-- {"jsonrpc":"2.0","id":1,"result":{}}
+--> {"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///nav.rn","languageId":"rune","version":1,"text":"fun add(a, b) {\n  var sum = a + b;\n  return sum;\n}\nclass Point {\n  init(x, y) {\n    this.x = x;\n  }\n  norm() {\n    fun square(n) { return n * n; }\n    return square(this.x);\n  }\n}\nvar total = add(1, 2);\nprint len([total]);\nprint add(total, 3);\nvar p = Point(1, 2);\n"}}}
+<-- {"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///nav.rn","diagnostics":[]}}
+--> {"jsonrpc":"2.0","id":2,"method":"textDocument/definition","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":15,"character":7}}}
+<-- {"jsonrpc":"2.0","id":2,"result":{"uri":"file:///nav.rn","range":{"start":{"line":0,"character":4},"end":{"line":0,"character":7}}}}
+--> {"jsonrpc":"2.0","id":3,"method":"textDocument/definition","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":2,"character":11}}}
+<-- {"jsonrpc":"2.0","id":3,"result":{"uri":"file:///nav.rn","range":{"start":{"line":1,"character":6},"end":{"line":1,"character":9}}}}
+--> {"jsonrpc":"2.0","id":4,"method":"textDocument/definition","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":14,"character":7}}}
+<-- {"jsonrpc":"2.0","id":4,"result":null}
+--> {"jsonrpc":"2.0","id":5,"method":"textDocument/references","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":13,"character":6},"context":{"includeDeclaration":true}}}
+<-- {"jsonrpc":"2.0","id":5,"result":[{"uri":"file:///nav.rn","range":{"start":{"line":13,"character":4},"end":{"line":13,"character":9}}},{"uri":"file:///nav.rn","range":{"start":{"line":14,"character":11},"end":{"line":14,"character":16}}},{"uri":"file:///nav.rn","range":{"start":{"line":15,"character":10},"end":{"line":15,"character":15}}}]}
+--> {"jsonrpc":"2.0","id":6,"method":"textDocument/references","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":1,"character":13},"context":{"includeDeclaration":false}}}
+<-- {"jsonrpc":"2.0","id":6,"result":[{"uri":"file:///nav.rn","range":{"start":{"line":1,"character":12},"end":{"line":1,"character":13}}}]}
+--> {"jsonrpc":"2.0","id":7,"method":"textDocument/definition","params":{"textDocument":{"uri":"file:///nav.rn"},"position":{"line":10,"character":13}}}
+<-- {"jsonrpc":"2.0","id":7,"result":{"uri":"file:///nav.rn","range":{"start":{"line":9,"character":8},"end":{"line":9,"character":14}}}}
+--> {"jsonrpc":"2.0","id":99,"method":"shutdown"}
+<-- {"jsonrpc":"2.0","id":99,"result":null}
+--> {"jsonrpc":"2.0","method":"exit"}
@@ -0,0 +1,907 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"rune/pkg/lsp"
+	"rune/pkg/rune"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// testDir holds the tests run by "rune test" without a directory.
+const testDir = "test"
+
+// lspExtension is the extension of the scripted exchanges with the language server.
+const lspExtension = ".lsp"
+
+// lintExtension is the extension of the programs checked by the linter, they expect the
+// warnings annotated on their lines.
+const lintExtension = ".lint"
+
+// Formatter tests are a program to format, with the extension fmtExtension, and the
+// expected result next to it with the extension goldenExtension.
+const (
+	fmtExtension    = ".fmt"
+	goldenExtension = ".golden"
+)
+
+// Annotations of the expected results in the comments of a test.
+var (
+	outputExpect       = regexp.MustCompile(`// expect: ?(.*)`)
+	errorExpect        = regexp.MustCompile(`// (Error.*)`)
+	errorLineExpect    = regexp.MustCompile(`// \[((java|c) )?line: (\d+)\] (Error.*)`)
+	runtimeErrorExpect = regexp.MustCompile(`// expect runtime error: (.+)`)
+	// traceExpect is a line of the stack trace under the runtime error, innermost
+	// first, e.g. "// expect trace: at fib (line 8)" or "// expect trace: ... 9 more frames".
+	traceExpect = regexp.MustCompile(`// expect trace: (.+)`)
+	// argsExpect adds options to the run, e.g. "// args: --max-steps=10".
+	argsExpect = regexp.MustCompile(`// args: (.+)`)
+	// warningExpect is a warning of the linter, with its rule, e.g.
+	// "// expect warning: Local 'a' is never used. [unused-variable]".
+	warningExpect = regexp.MustCompile(`// expect warning: (.+)`)
+
+	// In exchanges with the language server, the client sends the messages after -->
+	// and the server must answer with those after <--.
+	clientMessage = regexp.MustCompile(`^--> (.+)`)
+	serverMessage = regexp.MustCompile(`^<-- (.+)`)
+)
+
+// Patterns of the lines the interpreter writes to stderr.
+var (
+	syntaxErrorLine = regexp.MustCompile(`\[.*line: (\d+)\] (Error.+)`)
+	stackTraceLine  = regexp.MustCompile(`\[line: (\d+)\]`)
+	// sourceSnippetLine matches the source lines quoted under an error, e.g.
+	// " 3 | print a;" and the "   |       ^" underline.
+	sourceSnippetLine = regexp.MustCompile(`^ +\d* \| `)
+	// traceLine matches the frames listed under a runtime error, e.g. "  at fib (line 8)"
+	// and the "  ... 9 more frames" of a truncated trace.
+	traceLine = regexp.MustCompile(`^  (at .+|\.\.\. \d+ more frames)$`)
+)
+
+// testFile is a test script and the results annotated in its comments.
+type testFile struct {
+	path          string
+	output        []expectedOutput
+	compileErrors map[string]bool
+	runtimeError  string
+	trace         []string
+	warnings      []expectedOutput
+	exitCode      int
+	args          []string
+	expectations  int
+	// blocksOnly ignores the annotations, the test passes if the script runs and its
+	// test blocks pass. It tests libraries, whose output is not annotated.
+	blocksOnly bool
+	// exchange is the script of a language server test.
+	exchange []scriptedMessage
+}
+
+// scriptedMessage is a JSON-RPC message of an exchange with the language server.
+type scriptedMessage struct {
+	json   string
+	line   int
+	client bool
+}
+
+type expectedOutput struct {
+	text string
+	line int
+}
+
+// testResult is the outcome of a test, failures is empty if it passed. Those of its
+// test blocks are included.
+type testResult struct {
+	Path     string        `json:"path"`
+	Passed   bool          `json:"passed"`
+	Failures []string      `json:"failures,omitempty"`
+	Seconds  float64       `json:"seconds"`
+	Blocks   []blockResult `json:"blocks,omitempty"`
+}
+
+// blockResult is the outcome of a test block of a test.
+type blockResult struct {
+	Name    string `json:"name"`
+	Line    int    `json:"line"`
+	Passed  bool   `json:"passed"`
+	Failure string `json:"failure,omitempty"`
+}
+
+// testReport is the outcome of a test run, it is printed by --format=json.
+type testReport struct {
+	Engine       string       `json:"engine"`
+	Passed       int          `json:"passed"`
+	Failed       int          `json:"failed"`
+	Expectations int          `json:"expectations"`
+	Tests        []testResult `json:"tests"`
+}
+
+// runTests runs the test command: a file, the scripts in a directory, or those in test/
+// whose path starts with a filter, are run in parallel and checked against their
+// annotations. With --blocks only their test blocks are checked.
+func runTests(args []string) int {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	flags.Usage = printUsage
+	engine := flags.String("engine", "tree", "engine that runs the tests: tree or vm")
+	format := flags.String("format", "text", "output format: text, json or junit")
+	jobs := flags.Int("jobs", runtime.NumCPU(), "number of tests run at the same time")
+	blocksOnly := flags.Bool("blocks", false, "only check the test blocks of the scripts, not their annotations")
+	flags.Parse(args)
+
+	if flags.NArg() > 1 || *jobs < 1 {
+		printUsage()
+	}
+
+	root, filter := testDir, ""
+	if flags.NArg() == 1 {
+		// A directory or a file is tested as is, anything else filters the tests.
+		if _, err := os.Stat(flags.Arg(0)); err == nil {
+			root = flags.Arg(0)
+		} else {
+			filter = flags.Arg(0)
+		}
+	}
+
+	paths, err := findTests(root, filter, *blocksOnly)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding tests: %v\n", err)
+		return exitCodeError
+	}
+
+	var output func(results <-chan testResult, report *testReport)
+	switch *format {
+	case "text":
+		output = printTextResults
+	case "json":
+		output = func(results <-chan testResult, report *testReport) {
+			collectResults(results, report)
+			printJSONReport(report)
+		}
+	case "junit":
+		output = func(results <-chan testResult, report *testReport) {
+			collectResults(results, report)
+			printJUnitReport(report)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format: %s\n", *format)
+		return exitCodeError
+	}
+
+	tests := make([]*testFile, len(paths))
+	report := &testReport{Engine: *engine, Tests: []testResult{}}
+	for i, path := range paths {
+		if *blocksOnly {
+			tests[i] = &testFile{path: path, blocksOnly: true}
+			continue
+		}
+
+		tests[i] = parseTest(path)
+		report.Expectations += tests[i].expectations
+	}
+
+	output(runInParallel(tests, *engine, *jobs), report)
+
+	if report.Failed > 0 {
+		return exitCodeError
+	}
+
+	return exitCodeOk
+}
+
+// findTests returns the .rn, .lsp, .fmt and .lint files under root in lexical order, or
+// only the .rn files for blocksOnly. Those in test/ must start with filter relative to
+// it. Benchmarks are skipped. A root that is a file is the only test.
+func findTests(root string, filter string, blocksOnly bool) ([]string, error) {
+	extensions := []string{runeExtension, lspExtension, fmtExtension, lintExtension}
+	if blocksOnly {
+		extensions = []string{runeExtension}
+	}
+
+	var paths []string
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root && !entry.IsDir() {
+			paths = append(paths, filepath.ToSlash(path))
+			return nil
+		}
+
+		ext := filepath.Ext(path)
+		if entry.IsDir() || !slices.Contains(extensions, ext) || strings.Contains(path, "benchmark") {
+			return nil
+		}
+
+		if rel, err := filepath.Rel(root, path); err != nil || !strings.HasPrefix(filepath.ToSlash(rel), filter) {
+			return nil
+		}
+
+		paths = append(paths, filepath.ToSlash(path))
+
+		return nil
+	})
+
+	return paths, err
+}
+
+// parseTest reads the expectations of a test from its comments.
+func parseTest(path string) *testFile {
+	t := &testFile{path: path, compileErrors: make(map[string]bool)}
+
+	// The comments of a formatter test are part of what is formatted, it expects its
+	// golden file and that the golden file is formatted already.
+	if filepath.Ext(path) == fmtExtension {
+		t.expectations = 2
+		return t
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return t
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if match := clientMessage.FindStringSubmatch(line); match != nil {
+			t.exchange = append(t.exchange, scriptedMessage{match[1], lineNum, true})
+		}
+
+		if match := serverMessage.FindStringSubmatch(line); match != nil {
+			t.exchange = append(t.exchange, scriptedMessage{match[1], lineNum, false})
+			t.expectations++
+		}
+
+		if match := outputExpect.FindStringSubmatch(line); match != nil {
+			t.output = append(t.output, expectedOutput{match[1], lineNum})
+			t.expectations++
+		}
+
+		if match := errorExpect.FindStringSubmatch(line); match != nil {
+			t.compileErrors[match[1]] = true
+			t.exitCode = exitCodeParseError
+			t.expectations++
+		}
+
+		// Errors only reported by the C implementation of Lox do not apply.
+		if match := errorLineExpect.FindStringSubmatch(line); match != nil && match[2] != "c" {
+			t.compileErrors[match[4]] = true
+			t.exitCode = exitCodeParseError
+			t.expectations++
+		}
+
+		if match := warningExpect.FindStringSubmatch(line); match != nil {
+			t.warnings = append(t.warnings, expectedOutput{match[1], lineNum})
+			t.expectations++
+		}
+
+		if match := argsExpect.FindStringSubmatch(line); match != nil {
+			t.args = append(t.args, strings.Fields(match[1])...)
+		}
+
+		if match := runtimeErrorExpect.FindStringSubmatch(line); match != nil {
+			t.runtimeError = match[1]
+			t.exitCode = exitCodeEvalError
+			t.expectations++
+		}
+
+		if match := traceExpect.FindStringSubmatch(line); match != nil {
+			t.trace = append(t.trace, match[1])
+			t.expectations++
+		}
+	}
+
+	return t
+}
+
+// runInParallel runs the tests on jobs goroutines. The results are sent in the order
+// of the tests, the channel is closed after the last one.
+func runInParallel(tests []*testFile, engine string, jobs int) <-chan testResult {
+	done := make([]chan testResult, len(tests))
+	for i := range done {
+		done[i] = make(chan testResult, 1)
+	}
+
+	queue := make(chan int)
+	for range jobs {
+		go func() {
+			for i := range queue {
+				done[i] <- tests[i].run(engine)
+			}
+		}()
+	}
+
+	go func() {
+		for i := range tests {
+			queue <- i
+		}
+		close(queue)
+	}()
+
+	results := make(chan testResult)
+	go func() {
+		for _, result := range done {
+			results <- <-result
+		}
+		close(results)
+	}()
+
+	return results
+}
+
+// run runs the test in-process the way "rune run" would and checks its output.
+func (t *testFile) run(engine string) (result testResult) {
+	start := time.Now()
+	result.Path = t.path
+
+	defer func() {
+		if r := recover(); r != nil {
+			result.Failures = append(result.Failures, fmt.Sprintf("Interpreter panicked: %v", r))
+		}
+
+		result.Passed = len(result.Failures) == 0
+		result.Seconds = time.Since(start).Seconds()
+	}()
+
+	switch filepath.Ext(t.path) {
+	case lspExtension:
+		result.Failures = t.runExchange()
+		return result
+	case fmtExtension:
+		result.Failures = t.runFormat()
+		return result
+	case lintExtension:
+		result.Failures = t.runLint()
+		return result
+	}
+
+	flags := flag.NewFlagSet(t.path, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	options := runFlags(flags)
+	if err := flags.Parse(append([]string{"--engine=" + engine}, t.args...)); err != nil {
+		result.Failures = []string{fmt.Sprintf("Test error: Invalid args: %v", err)}
+		return result
+	}
+
+	source, err := os.ReadFile(t.path)
+	if err != nil {
+		result.Failures = []string{fmt.Sprintf("Test error: %v", err)}
+		return result
+	}
+
+	var stdout, stderr bytes.Buffer
+	opts := options()
+	opts.Stdout = &stdout
+	opts.Stderr = &stderr
+
+	opts.Path = t.path
+
+	code, blocks := runTestBlocks(source, opts)
+	if t.blocksOnly {
+		result.Failures = t.validateRun(code, stderr.String())
+	} else {
+		result.Failures = t.validate(code, stdout.String(), stderr.String())
+	}
+
+	for _, block := range blocks {
+		result.Blocks = append(result.Blocks, t.blockResult(block))
+		if block.Err != nil {
+			result.Failures = append(result.Failures, result.Blocks[len(result.Blocks)-1].Failure)
+		}
+	}
+
+	return result
+}
+
+// runTestBlocks runs a program like "rune run" does, then its test blocks. It returns
+// the exit code of the run and the outcome of the blocks.
+func runTestBlocks(source []byte, opts rune.Options) (int, []rune.TestResult) {
+	r, err := rune.NewRuntime(opts)
+	if err != nil {
+		fmt.Fprintf(opts.Stderr, "Error: %v\n", err)
+		return exitCodeError, nil
+	}
+
+	results, err := r.RunTests(context.Background(), source)
+
+	return exitCode(err), results
+}
+
+// blockResult describes the outcome of a test block, a failure points at the line that
+// raised the error.
+func (t *testFile) blockResult(block rune.TestResult) blockResult {
+	result := blockResult{Name: block.Name, Line: block.Line, Passed: block.Err == nil}
+	if block.Err == nil {
+		return result
+	}
+
+	line, message := block.Line, block.Err.Error()
+	if e, ok := block.Err.(interface {
+		Line() int
+		Message() string
+	}); ok {
+		line, message = e.Line(), e.Message()
+	}
+
+	result.Failure = fmt.Sprintf("%s:%d: test \"%s\" failed: %s", t.path, line, block.Name, message)
+
+	return result
+}
+
+// runExchange sends the client messages of a language server test to an in-process
+// server and checks that it answers with the expected messages, in order. Expected
+// objects only need to match the keys they list.
+func (t *testFile) runExchange() []string {
+	var in, out bytes.Buffer
+	for _, msg := range t.exchange {
+		if msg.client {
+			fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(msg.json), msg.json)
+		}
+	}
+
+	var failures []string
+	if code := lsp.NewServer(version).Serve(&in, &out); code != exitCodeOk {
+		failures = append(failures, fmt.Sprintf("Expected the server to exit with %d and got %d.", exitCodeOk, code))
+	}
+
+	reader := bufio.NewReader(&out)
+	for _, msg := range t.exchange {
+		if msg.client {
+			continue
+		}
+
+		var expected any
+		if err := json.Unmarshal([]byte(msg.json), &expected); err != nil {
+			return append(failures, fmt.Sprintf("Test error: Invalid message on line %d: %v", msg.line, err))
+		}
+
+		body, err := lsp.ReadMessage(reader)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("Missing expected message on line %d: %s", msg.line, msg.json))
+			continue
+		}
+
+		var actual any
+		if err := json.Unmarshal(body, &actual); err != nil || !matchJSON(expected, actual) {
+			failures = append(failures, fmt.Sprintf("Expected message on line %d: %s", msg.line, msg.json), fmt.Sprintf("and got: %s", body))
+		}
+	}
+
+	for {
+		body, err := lsp.ReadMessage(reader)
+		if err != nil {
+			break
+		}
+
+		failures = append(failures, fmt.Sprintf("Got message when none was expected: %s", body))
+	}
+
+	return failures
+}
+
+// runFormat formats the program of a formatter test and checks that the result is its
+// golden file, and that formatting the golden file leaves it unchanged.
+func (t *testFile) runFormat() []string {
+	source, err := os.ReadFile(t.path)
+	if err != nil {
+		return []string{fmt.Sprintf("Test error: %v", err)}
+	}
+
+	goldenPath := strings.TrimSuffix(t.path, fmtExtension) + goldenExtension
+	golden, err := os.ReadFile(goldenPath)
+	if err != nil {
+		return []string{fmt.Sprintf("Test error: %v", err)}
+	}
+
+	formatted, err := rune.Format(source)
+	if err != nil {
+		return []string{fmt.Sprintf("Formatting failed: %v", err)}
+	}
+
+	var failures []string
+	if !bytes.Equal(formatted, golden) {
+		failures = append(failures, fmt.Sprintf("Expected the formatted program to be %s and got:", goldenPath), string(formatted))
+	}
+
+	again, err := rune.Format(golden)
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("Formatting %s failed: %v", goldenPath, err))
+	} else if !bytes.Equal(again, golden) {
+		failures = append(failures, fmt.Sprintf("Expected %s to be formatted already and got:", goldenPath), string(again))
+	}
+
+	return failures
+}
+
+// runLint lints the program of a linter test with the rules picked by its args and
+// checks that it gets the expected warnings, each on the line annotated with it.
+func (t *testFile) runLint() []string {
+	flags := flag.NewFlagSet(t.path, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	rules := lintFlags(flags)
+	if err := flags.Parse(t.args); err != nil {
+		return []string{fmt.Sprintf("Test error: Invalid args: %v", err)}
+	}
+
+	enabled, err := rules()
+	if err != nil {
+		return []string{fmt.Sprintf("Test error: Invalid args: %v", err)}
+	}
+
+	source, err := os.ReadFile(t.path)
+	if err != nil {
+		return []string{fmt.Sprintf("Test error: %v", err)}
+	}
+
+	warnings, err := rune.Lint(source, enabled)
+	if err != nil {
+		return []string{fmt.Sprintf("Linting failed: %v", err)}
+	}
+
+	expected := slices.Clone(t.warnings)
+
+	var failures []string
+	for _, w := range warnings {
+		text := fmt.Sprintf("%s [%s]", w.Message, w.Rule)
+
+		i := slices.Index(expected, expectedOutput{text, w.Token.Line})
+		if i < 0 {
+			failures = append(failures, fmt.Sprintf("Unexpected warning on line %d: %s", w.Token.Line, text))
+			continue
+		}
+
+		expected = slices.Delete(expected, i, i+1)
+	}
+
+	for _, e := range expected {
+		failures = append(failures, fmt.Sprintf("Missing expected warning on line %d: %s", e.line, e.text))
+	}
+
+	return failures
+}
+
+// matchJSON reports whether a decoded JSON value matches the expected one. Objects match
+// when the actual one has every expected key with a matching value, arrays when they
+// have as many matching items.
+func matchJSON(expected any, actual any) bool {
+	switch expected := expected.(type) {
+	case map[string]any:
+		actual, ok := actual.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		for key, value := range expected {
+			if other, ok := actual[key]; !ok || !matchJSON(value, other) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		actual, ok := actual.([]any)
+		return ok && slices.EqualFunc(expected, actual, matchJSON)
+	}
+
+	return expected == actual
+}
+
+func (t *testFile) validate(exitCode int, out string, err string) []string {
+	if len(t.compileErrors) > 0 && t.runtimeError != "" {
+		return []string{"Test error: Cannot expect both compile and runtime errors."}
+	}
+
+	var failures []string
+
+	errorLines := strings.Split(err, "\n")
+	if t.runtimeError != "" {
+		failures = append(failures, t.validateRuntimeError(errorLines)...)
+	} else {
+		failures = append(failures, t.validateCompileErrors(errorLines)...)
+	}
+
+	failures = append(failures, t.validateExitCode(exitCode, errorLines)...)
+
+	return append(failures, t.validateOutput(out)...)
+}
+
+// validateRun checks that the script of a test run with --blocks ran without errors.
+func (t *testFile) validateRun(exitCode int, err string) []string {
+	if exitCode == exitCodeOk {
+		return nil
+	}
+
+	failures := []string{fmt.Sprintf("Expected the script to run and it exited with %d:", exitCode)}
+
+	return append(failures, strings.Split(strings.TrimSuffix(err, "\n"), "\n")...)
+}
+
+func (t *testFile) validateRuntimeError(errorLines []string) []string {
+	if len(errorLines) < 2 {
+		return []string{fmt.Sprintf("Expected runtime error \"%s\" and got none.", t.runtimeError)}
+	}
+
+	// Skip any compile errors. This can happen if there is a compile error in a module
+	// loaded by the module being tested.
+	line := 0
+	for line < len(errorLines)-1 && (syntaxErrorLine.MatchString(errorLines[line]) || sourceSnippetLine.MatchString(errorLines[line])) {
+		line++
+	}
+
+	var failures []string
+	if errorLines[line] != t.runtimeError {
+		failures = append(failures,
+			fmt.Sprintf("Expected runtime error \"%s\" and got:", t.runtimeError),
+			errorLines[line])
+	}
+
+	if len(t.trace) > 0 {
+		return append(failures, t.validateTrace(errorLines[line+1:])...)
+	}
+
+	// Make sure the error comes with a stack trace.
+	for _, stackLine := range errorLines[line:] {
+		if stackTraceLine.MatchString(stackLine) {
+			return failures
+		}
+	}
+
+	failures = append(failures, "Expected stack trace and got:")
+
+	return append(failures, errorLines[line:]...)
+}
+
+// validateTrace compares the frames listed under the runtime error with the expected
+// trace, line by line.
+func (t *testFile) validateTrace(errorLines []string) []string {
+	var trace []string
+	for _, line := range errorLines {
+		if match := traceLine.FindStringSubmatch(line); match != nil {
+			trace = append(trace, match[1])
+		}
+	}
+
+	if slices.Equal(trace, t.trace) {
+		return nil
+	}
+
+	failures := []string{"Expected trace:"}
+	for _, frame := range t.trace {
+		failures = append(failures, "  "+frame)
+	}
+
+	failures = append(failures, "And got:")
+	for _, frame := range trace {
+		failures = append(failures, "  "+frame)
+	}
+
+	return failures
+}
+
+func (t *testFile) validateCompileErrors(errorLines []string) []string {
+	var failures []string
+
+	// Validate that every compile error was expected.
+	found := make(map[string]bool)
+	unexpected := 0
+	for _, line := range errorLines {
+		if sourceSnippetLine.MatchString(line) {
+			continue
+		}
+
+		if match := syntaxErrorLine.FindStringSubmatch(line); match != nil {
+			if t.compileErrors[match[2]] {
+				found[match[2]] = true
+				continue
+			}
+
+			if unexpected < 10 {
+				failures = append(failures, "Unexpected error:", line)
+			}
+			unexpected++
+		} else if line != "" {
+			if unexpected < 10 {
+				failures = append(failures, "Unexpected output on stderr:", line)
+			}
+			unexpected++
+		}
+	}
+
+	if unexpected > 10 {
+		failures = append(failures, fmt.Sprintf("(truncated %d more...)", unexpected-10))
+	}
+
+	// Validate that every expected error occurred.
+	for expected := range t.compileErrors {
+		if !found[expected] {
+			failures = append(failures, fmt.Sprintf("Missing expected error: %s", expected))
+		}
+	}
+
+	return failures
+}
+
+func (t *testFile) validateExitCode(exitCode int, errorLines []string) []string {
+	if exitCode == t.exitCode {
+		return nil
+	}
+
+	if len(errorLines) > 10 {
+		errorLines = append(errorLines[:10:10], "(truncated...)")
+	}
+
+	failures := []string{fmt.Sprintf("Expected return code %d and got %d. Stderr:", t.exitCode, exitCode)}
+
+	return append(failures, errorLines...)
+}
+
+func (t *testFile) validateOutput(out string) []string {
+	var failures []string
+
+	// Remove the trailing last empty line.
+	outLines := strings.Split(out, "\n")
+	if outLines[len(outLines)-1] == "" {
+		outLines = outLines[:len(outLines)-1]
+	}
+
+	for i, line := range outLines {
+		if i >= len(t.output) {
+			failures = append(failures, fmt.Sprintf("Got output \"%s\" when none was expected.", line))
+		} else if t.output[i].text != line {
+			failures = append(failures, fmt.Sprintf("Expected output \"%s\" on line %d and got \"%s\".", t.output[i].text, t.output[i].line, line))
+		}
+	}
+
+	for _, expected := range t.output[min(len(outLines), len(t.output)):] {
+		failures = append(failures, fmt.Sprintf("Missing expected output \"%s\" on line %d.", expected.text, expected.line))
+	}
+
+	return failures
+}
+
+// collectResults adds the results to the report as they come in.
+func collectResults(results <-chan testResult, report *testReport) {
+	for result := range results {
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+
+		report.Tests = append(report.Tests, result)
+	}
+}
+
+// printTextResults prints each result as it comes in, followed by a summary.
+func printTextResults(results <-chan testResult, report *testReport) {
+	blocks := 0
+	for result := range results {
+		blocks += len(result.Blocks)
+
+		if result.Passed {
+			report.Passed++
+			fmt.Printf("PASS: %s%s\n", result.Path, blockCounts(result.Blocks))
+			continue
+		}
+
+		report.Failed++
+		fmt.Printf("FAIL: %s%s\n\n", result.Path, blockCounts(result.Blocks))
+		for _, failure := range result.Failures {
+			fmt.Printf("      %s\n", failure)
+		}
+	}
+
+	if report.Failed == 0 && blocks > 0 && report.Expectations == 0 {
+		fmt.Printf("All %d tests passed (%d test blocks).\n", report.Passed, blocks)
+	} else if report.Failed == 0 && blocks > 0 {
+		fmt.Printf("All %d tests passed (%d expectations, %d test blocks).\n", report.Passed, report.Expectations, blocks)
+	} else if report.Failed == 0 {
+		fmt.Printf("All %d tests passed (%d expectations).\n", report.Passed, report.Expectations)
+	} else {
+		fmt.Printf("%d tests passed. %d tests failed.\n", report.Passed, report.Failed)
+	}
+}
+
+// blockCounts describes how many test blocks of a script passed, e.g. " (2 passed, 1
+// failed)". It is empty for scripts without blocks.
+func blockCounts(blocks []blockResult) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+
+	passed := 0
+	for _, block := range blocks {
+		if block.Passed {
+			passed++
+		}
+	}
+
+	return fmt.Sprintf(" (%d passed, %d failed)", passed, len(blocks)-passed)
+}
+
+func printJSONReport(report *testReport) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// printJUnitReport prints the report as JUnit XML, with one test case per script named
+// after its file and classed by its directory.
+func printJUnitReport(report *testReport) {
+	suite := junitTestSuite{
+		Name:     "rune (" + report.Engine + ")",
+		Tests:    len(report.Tests),
+		Failures: report.Failed,
+	}
+
+	var total float64
+	for _, result := range report.Tests {
+		total += result.Seconds
+
+		testCase := junitTestCase{
+			Name:      filepath.Base(result.Path),
+			Classname: strings.ReplaceAll(strings.TrimPrefix(filepath.Dir(result.Path), "/"), "/", "."),
+			Time:      seconds(result.Seconds),
+		}
+
+		if !result.Passed {
+			testCase.Failure = &junitFailure{
+				Message: result.Failures[0],
+				Text:    strings.Join(result.Failures, "\n"),
+			}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	suite.Time = seconds(total)
+
+	out, _ := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	fmt.Printf("%s%s\n", xml.Header, out)
+}
+
+func seconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
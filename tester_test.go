@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeScript writes a script to a temporary directory and returns its path.
+func writeScript(t *testing.T, name string, source string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.ToSlash(path)
+}
+
+func TestFindTestsFile(t *testing.T) {
+	path := writeScript(t, "lib.rn", "print 1;")
+
+	paths, err := findTests(path, "", false)
+	if err != nil || !slices.Equal(paths, []string{path}) {
+		t.Errorf("got %v, %v, want only %s", paths, err, path)
+	}
+}
+
+func TestFindTestsBlocksOnly(t *testing.T) {
+	paths, err := findTests("test/fmt", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		if filepath.Ext(path) != runeExtension {
+			t.Errorf("got %s, want only .rn files", path)
+		}
+	}
+}
+
+func TestBlocksOnly(t *testing.T) {
+	source := `fun add(a, b) { return a + b; }
+print "loaded";
+
+test "adds" {
+  assertEqual(add(1, 2), 3);
+}
+
+test "fails" {
+  assertEqual(add(1, 1), 3);
+}
+`
+	path := writeScript(t, "mylib.rn", source)
+
+	// The unannotated output fails the file with the annotations checked.
+	if result := parseTest(path).run("tree"); !slices.ContainsFunc(result.Failures, func(f string) bool {
+		return strings.HasPrefix(f, `Got output "loaded"`)
+	}) {
+		t.Errorf("got failures %q, want the unexpected output", result.Failures)
+	}
+
+	result := (&testFile{path: path, blocksOnly: true}).run("tree")
+	if result.Passed || len(result.Failures) != 1 || !strings.Contains(result.Failures[0], `test "fails" failed: Expected 3 but got 2.`) {
+		t.Errorf("got failures %q, want only the failed block", result.Failures)
+	}
+
+	if counts := blockCounts(result.Blocks); counts != " (1 passed, 1 failed)" {
+		t.Errorf("got counts %q", counts)
+	}
+}
+
+func TestBlocksOnlyScriptError(t *testing.T) {
+	path := writeScript(t, "broken.rn", "print nil.x;\ntest \"never\" {}\n")
+
+	result := (&testFile{path: path, blocksOnly: true}).run("vm")
+	if result.Passed || len(result.Failures) < 2 || result.Failures[1] != "[line: 1] Only objects have properties." {
+		t.Errorf("got failures %q, want the error of the script", result.Failures)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	source := `fun inner() {
+  return nil.x;
+}
+
+fun outer() {
+  return inner();
+}
+
+outer();
+// expect runtime error: [line: 2] Only objects have properties.
+// expect trace: at inner (line 2)
+// expect trace: at <script> (line 9)
+`
+	path := writeScript(t, "trace.rn", source)
+
+	for _, engine := range []string{"tree", "vm"} {
+		result := parseTest(path).run(engine)
+		if result.Passed || !slices.Contains(result.Failures, "  at outer (line 6)") {
+			t.Errorf("%s: got failures %q, want the missing frame", engine, result.Failures)
+		}
+	}
+}